@@ -0,0 +1,759 @@
+// Package clustertest
+//
+// English:
+//
+//  Starts a cluster of demo.Server in the same process, on the loopback interface, so the tests of
+//  joins, failures and data synchronism run with go test, in seconds, without Docker.
+//
+//   Example:
+//     var cluster, err = clustertest.New(clustertest.Options{Nodes: 3})
+//     if err != nil {
+//       t.Fatal(err)
+//     }
+//     defer cluster.Close()
+//
+//     err = cluster.WaitForConvergence(5 * time.Second)
+//
+// Português:
+//
+//  Inicia um cluster de demo.Server no mesmo processo, na interface de loopback, assim os testes de
+//  entrada, falhas e sincronismo de dados rodam com go test, em segundos, sem Docker.
+//
+//   Exemplo:
+//     var cluster, err = clustertest.New(clustertest.Options{Nodes: 3})
+//     if err != nil {
+//       t.Fatal(err)
+//     }
+//     defer cluster.Close()
+//
+//     err = cluster.WaitForConvergence(5 * time.Second)
+package clustertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	demo "github.com/helmutkemper/iotmaker.docker.builder.demo"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//kDefaultNodes
+	//
+	// English:
+	//
+	// Number of nodes when Options.Nodes is zero.
+	//
+	// Português:
+	//
+	// Número de nodes quando Options.Nodes é zero.
+	kDefaultNodes = 3
+
+	//kDefaultBindAddress
+	//
+	// English:
+	//
+	// Address of the nodes when Options.BindAddress is empty.
+	//
+	// Português:
+	//
+	// Endereço dos nodes quando Options.BindAddress é vazio.
+	kDefaultBindAddress = "127.0.0.1"
+
+	//kPollInterval
+	//
+	// English:
+	//
+	// Interval between the checks of WaitFor().
+	//
+	// Português:
+	//
+	// Intervalo entre as verificações de WaitFor().
+	kPollInterval = 50 * time.Millisecond
+)
+
+// ServiceName
+//
+// English: name of the SRV records of the running nodes, published by the Cluster in Resolver()
+//
+// Português: nome dos registros SRV dos nodes rodando, publicados pelo Cluster em Resolver()
+const ServiceName = "cluster.test"
+
+var (
+	// ErrNotConverged
+	//
+	// English: the condition was not satisfied before the timeout
+	//
+	// Português: a condição não foi satisfeita antes do tempo limite
+	ErrNotConverged = errors.New("cluster did not converge")
+
+	// ErrInvalidNode
+	//
+	// English: the index doesn't belong to a node of the cluster
+	//
+	// Português: o índice não pertence a um node do cluster
+	ErrInvalidNode = errors.New("invalid node index")
+
+	// ErrNodeRunning
+	//
+	// English: the node is already running
+	//
+	// Português: o node já está rodando
+	ErrNodeRunning = errors.New("node is running")
+
+	// ErrNodeStopped
+	//
+	// English: the node is not running
+	//
+	// Português: o node não está rodando
+	ErrNodeStopped = errors.New("node is stopped")
+)
+
+// Options
+//
+// English:
+//
+//  Configuration of the cluster
+//
+//   Fields:
+//     Nodes: number of nodes, by default, 3
+//     BindAddress: loopback address of the nodes, by default, 127.0.0.1
+//     Logger: logger of the nodes. By default, the messages are discarded
+//     Configure: called for each node before Init(), to change the configuration of the node
+//
+// Português:
+//
+//  Configuração do cluster
+//
+//   Campos:
+//     Nodes: número de nodes, por padrão, 3
+//     BindAddress: endereço de loopback dos nodes, por padrão, 127.0.0.1
+//     Logger: logger dos nodes. Por padrão, as mensagens são descartadas
+//     Configure: chamado para cada node antes de Init(), para mudar a configuração do node
+type Options struct {
+	Nodes       int
+	BindAddress string
+	Logger      demo.Logger
+	Configure   func(index int, server *demo.Server)
+}
+
+// node
+//
+// English:
+//
+//  One node of the cluster
+//
+// Português:
+//
+//  Um node do cluster
+type node struct {
+	server  *demo.Server
+	port    int
+	running bool
+}
+
+// Cluster
+//
+// English:
+//
+//  Set of demo.Server running in the same process. The nodes find each other by the SRV records of
+//  ServiceName, answered by a fake resolver, see Resolver()
+//
+// Português:
+//
+//  Conjunto de demo.Server rodando no mesmo processo. Os nodes se encontram pelos registros SRV de
+//  ServiceName, respondidos por um resolvedor falso, veja Resolver()
+type Cluster struct {
+	mutex    sync.Mutex
+	options  Options
+	resolver *FakeResolver
+	nodes    []*node
+}
+
+// New
+//
+// English:
+//
+//  Starts the cluster, one node after the other, each node joining the previous ones
+//
+//   Input:
+//     options: configuration of the cluster
+//
+//   Output:
+//     cluster: cluster running
+//     err: standard error object. In case of error, the nodes already started are stopped
+//
+//   Note:
+//     * The timings of the nodes are reduced, so failures are detected in about one second.
+//
+// Português:
+//
+//  Inicia o cluster, um node após o outro, cada node entrando nos anteriores
+//
+//   Entrada:
+//     options: configuração do cluster
+//
+//   Saída:
+//     cluster: cluster rodando
+//     err: objeto de erro padrão. Em caso de erro, os nodes já iniciados são parados
+//
+//   Nota:
+//     * Os tempos dos nodes são reduzidos, assim as falhas são detectadas em cerca de um segundo.
+func New(options Options) (cluster *Cluster, err error) {
+	if options.Nodes <= 0 {
+		options.Nodes = kDefaultNodes
+	}
+
+	if options.BindAddress == "" {
+		options.BindAddress = kDefaultBindAddress
+	}
+
+	if options.Logger == nil {
+		options.Logger = demo.NewLogger(io.Discard, demo.LogLevelError, demo.LogEncoderText)
+	}
+
+	cluster = &Cluster{
+		options:  options,
+		resolver: &FakeResolver{},
+	}
+
+	for i := 0; i < options.Nodes; i += 1 {
+		_, err = cluster.AddNode()
+		if err != nil {
+			_ = cluster.Close()
+			cluster = nil
+			return
+		}
+	}
+
+	return
+}
+
+// Size
+//
+// English:
+//
+//  Returns the number of nodes, running or not
+//
+// Português:
+//
+//  Retorna o número de nodes, rodando ou não
+func (e *Cluster) Size() (size int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return len(e.nodes)
+}
+
+// Node
+//
+// English:
+//
+//  Returns the server of the node, nil for an invalid index
+//
+//   Note:
+//     * RestartNode() replaces the server, call Node() again after it.
+//
+// Português:
+//
+//  Retorna o servidor do node, nil para um índice inválido
+//
+//   Nota:
+//     * RestartNode() troca o servidor, chame Node() novamente depois dele.
+func (e *Cluster) Node(index int) (server *demo.Server) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if index < 0 || index >= len(e.nodes) {
+		return
+	}
+
+	return e.nodes[index].server
+}
+
+// Name
+//
+// English:
+//
+//  Returns the name of the node, node-<index>
+//
+// Português:
+//
+//  Retorna o nome do node, node-<índice>
+func (e *Cluster) Name(index int) (name string) {
+	return "node-" + strconv.Itoa(index)
+}
+
+// Running
+//
+// English:
+//
+//  Returns the indexes of the running nodes
+//
+// Português:
+//
+//  Retorna os índices dos nodes rodando
+func (e *Cluster) Running() (indexes []int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	indexes = make([]int, 0, len(e.nodes))
+	for index, node := range e.nodes {
+		if node.running == true {
+			indexes = append(indexes, index)
+		}
+	}
+
+	return
+}
+
+// Resolver
+//
+// English:
+//
+//  Returns the fake resolver used by all nodes. The Cluster publishes the running nodes as SRV
+//  records of ServiceName at each start and stop of a node
+//
+//   Example:
+//     // the discovery fails until the next start or stop of a node
+//     cluster.Resolver().SetNotFound(clustertest.ServiceName)
+//
+// Português:
+//
+//  Retorna o resolvedor falso usado por todos os nodes. O Cluster publica os nodes rodando como
+//  registros SRV de ServiceName a cada início e parada de um node
+//
+//   Exemplo:
+//     // a descoberta falha até o próximo início ou parada de um node
+//     cluster.Resolver().SetNotFound(clustertest.ServiceName)
+func (e *Cluster) Resolver() (resolver *FakeResolver) {
+	return e.resolver
+}
+
+// AddNode
+//
+// English:
+//
+//  Starts a new node, which joins the running nodes
+//
+//   Output:
+//     index: index of the new node
+//     err: standard error object
+//
+// Português:
+//
+//  Inicia um novo node, que entra nos nodes rodando
+//
+//   Saída:
+//     index: índice do novo node
+//     err: objeto de erro padrão
+func (e *Cluster) AddNode() (index int, err error) {
+	var port int
+	port, err = freePort(e.options.BindAddress)
+	if err != nil {
+		return
+	}
+
+	e.mutex.Lock()
+	index = len(e.nodes)
+	e.nodes = append(e.nodes, &node{port: port})
+	e.mutex.Unlock()
+
+	err = e.start(index)
+	return
+}
+
+// KillNode
+//
+// English:
+//
+//  Stops the node without leaving the cluster, as in a crash, see demo.Server.Kill()
+//
+//   Input:
+//     index: index of the node
+//
+//   Output:
+//     err: ErrInvalidNode, ErrNodeStopped or the error of Kill()
+//
+// Português:
+//
+//  Para o node sem sair do cluster, como em uma falha, veja demo.Server.Kill()
+//
+//   Entrada:
+//     index: índice do node
+//
+//   Saída:
+//     err: ErrInvalidNode, ErrNodeStopped ou o erro de Kill()
+func (e *Cluster) KillNode(index int) (err error) {
+	var server *demo.Server
+	server, err = e.stop(index)
+	if err != nil {
+		return
+	}
+
+	err = server.Kill()
+	return
+}
+
+// StopNode
+//
+// English:
+//
+//  Stops the node leaving the cluster, see demo.Server.Shutdown()
+//
+//   Input:
+//     ctx: limits the time of the leave
+//     index: index of the node
+//
+//   Output:
+//     err: ErrInvalidNode, ErrNodeStopped or the error of Shutdown()
+//
+// Português:
+//
+//  Para o node saindo do cluster, veja demo.Server.Shutdown()
+//
+//   Entrada:
+//     ctx: limita o tempo da saída
+//     index: índice do node
+//
+//   Saída:
+//     err: ErrInvalidNode, ErrNodeStopped ou o erro de Shutdown()
+func (e *Cluster) StopNode(ctx context.Context, index int) (err error) {
+	var server *demo.Server
+	server, err = e.stop(index)
+	if err != nil {
+		return
+	}
+
+	_, err = server.Shutdown(ctx)
+	return
+}
+
+// RestartNode
+//
+// English:
+//
+//  Starts a stopped node again, with a new server, the same name and the same port
+//
+//   Input:
+//     index: index of the node
+//
+//   Output:
+//     err: ErrInvalidNode, ErrNodeRunning or the error of Init()
+//
+//   Note:
+//     * A member that missed the gossip of the restarted node only sees it after the next push/pull,
+//       up to 15 seconds in the local network profile.
+//
+// Português:
+//
+//  Inicia novamente um node parado, com um novo servidor, o mesmo nome e a mesma porta
+//
+//   Entrada:
+//     index: índice do node
+//
+//   Saída:
+//     err: ErrInvalidNode, ErrNodeRunning ou o erro de Init()
+//
+//   Nota:
+//     * Um membro que perdeu o gossip do node reiniciado só o vê depois do próximo push/pull, até 15
+//       segundos no perfil de rede local.
+func (e *Cluster) RestartNode(index int) (err error) {
+	return e.start(index)
+}
+
+// WaitForConvergence
+//
+// English:
+//
+//  Waits until each running node sees exactly the running nodes as alive members
+//
+//   Input:
+//     timeout: maximum wait
+//
+//   Output:
+//     err: ErrNotConverged, with the view of the first node that didn't converge
+//
+// Português:
+//
+//  Espera até cada node rodando ver exatamente os nodes rodando como membros vivos
+//
+//   Entrada:
+//     timeout: espera máxima
+//
+//   Saída:
+//     err: ErrNotConverged, com a visão do primeiro node que não convergiu
+func (e *Cluster) WaitForConvergence(timeout time.Duration) (err error) {
+	var detail string
+	err = e.WaitFor(timeout, func() bool {
+		detail = e.divergence()
+		return detail == ""
+	})
+	if err != nil {
+		err = fmt.Errorf("%w: %v", err, detail)
+	}
+
+	return
+}
+
+// WaitFor
+//
+// English:
+//
+//  Waits until the condition returns true
+//
+//   Input:
+//     timeout: maximum wait
+//     condition: function called periodically
+//
+//   Output:
+//     err: ErrNotConverged when the timeout expires
+//
+// Português:
+//
+//  Espera até a condição retornar true
+//
+//   Entrada:
+//     timeout: espera máxima
+//     condition: função chamada periodicamente
+//
+//   Saída:
+//     err: ErrNotConverged quando o tempo limite expira
+func (e *Cluster) WaitFor(timeout time.Duration, condition func() bool) (err error) {
+	var deadline = time.Now().Add(timeout)
+	for {
+		if condition() == true {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			err = ErrNotConverged
+			return
+		}
+
+		time.Sleep(kPollInterval)
+	}
+}
+
+// Close
+//
+// English:
+//
+//  Stops all running nodes, leaving the cluster
+//
+//   Output:
+//     err: first error of Shutdown()
+//
+// Português:
+//
+//  Para todos os nodes rodando, saindo do cluster
+//
+//   Saída:
+//     err: primeiro erro de Shutdown()
+func (e *Cluster) Close() (err error) {
+	var wait sync.WaitGroup
+	var errMutex sync.Mutex
+
+	for _, index := range e.Running() {
+		wait.Add(1)
+		go func(index int) {
+			defer wait.Done()
+
+			var ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			var errStop = e.StopNode(ctx, index)
+			errMutex.Lock()
+			if err == nil {
+				err = errStop
+			}
+			errMutex.Unlock()
+		}(index)
+	}
+
+	wait.Wait()
+	return
+}
+
+// start
+//
+// English:
+//
+//  Creates, configures and initializes the server of the node
+//
+// Português:
+//
+//  Cria, configura e inicializa o servidor do node
+func (e *Cluster) start(index int) (err error) {
+	e.mutex.Lock()
+	if index < 0 || index >= len(e.nodes) {
+		e.mutex.Unlock()
+		err = ErrInvalidNode
+		return
+	}
+
+	var node = e.nodes[index]
+	if node.running == true {
+		e.mutex.Unlock()
+		err = ErrNodeRunning
+		return
+	}
+	e.mutex.Unlock()
+
+	var server = &demo.Server{}
+	server.SetNodeName(e.Name(index))
+	server.SetBindAddress(e.options.BindAddress)
+	server.SetNetworkProfile(demo.NetworkProfileLocal)
+	server.SetGossipInterval(50 * time.Millisecond)
+	server.SetProbeInterval(200*time.Millisecond, 100*time.Millisecond)
+	server.SetDnsCheckInterval(100 * time.Millisecond)
+	server.SetLogger(e.options.Logger)
+	server.SetResolver(e.resolver)
+	server.AddDiscovery(&demo.DiscoveryDnsSrv{Name: ServiceName})
+	if e.options.Configure != nil {
+		e.options.Configure(index, server)
+	}
+
+	err = server.Init(node.port)
+	if err != nil {
+		_ = server.Kill()
+		return
+	}
+
+	e.mutex.Lock()
+	node.server = server
+	node.running = true
+	e.publish()
+	e.mutex.Unlock()
+
+	return
+}
+
+// stop
+//
+// English:
+//
+//  Marks the node as stopped and removes its record from the resolver
+//
+// Português:
+//
+//  Marca o node como parado e remove o seu registro do resolvedor
+func (e *Cluster) stop(index int) (server *demo.Server, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if index < 0 || index >= len(e.nodes) {
+		err = ErrInvalidNode
+		return
+	}
+
+	var node = e.nodes[index]
+	if node.running == false {
+		err = ErrNodeStopped
+		return
+	}
+
+	node.running = false
+	e.publish()
+
+	server = node.server
+	return
+}
+
+// publish
+//
+// English:
+//
+//  Replaces the SRV records of ServiceName by the records of the running nodes. Must be called with
+//  the mutex locked
+//
+// Português:
+//
+//  Troca os registros SRV de ServiceName pelos registros dos nodes rodando. Deve ser chamado com o
+//  mutex travado
+func (e *Cluster) publish() {
+	var records = make([]net.SRV, 0, len(e.nodes))
+	for _, node := range e.nodes {
+		if node.running == true {
+			records = append(records, net.SRV{Target: e.options.BindAddress, Port: uint16(node.port)})
+		}
+	}
+
+	e.resolver.SetSRV(ServiceName, records...)
+}
+
+// divergence
+//
+// English:
+//
+//  Returns the view of the first running node whose alive members differ from the running nodes,
+//  empty when all nodes converged
+//
+// Português:
+//
+//  Retorna a visão do primeiro node rodando cujos membros vivos diferem dos nodes rodando, vazio
+//  quando todos os nodes convergiram
+func (e *Cluster) divergence() (detail string) {
+	var running = e.Running()
+
+	var expected = make([]string, 0, len(running))
+	for _, index := range running {
+		expected = append(expected, e.Name(index))
+	}
+	sort.Strings(expected)
+
+	for _, index := range running {
+		var seen = make([]string, 0, len(expected))
+		for _, member := range e.Node(index).Members() {
+			if member.State == demo.MemberAlive {
+				seen = append(seen, member.Name)
+			}
+		}
+		sort.Strings(seen)
+
+		if strings.Join(seen, ",") != strings.Join(expected, ",") {
+			return fmt.Sprintf("%v sees [%v], expected [%v]", e.Name(index), strings.Join(seen, ","), strings.Join(expected, ","))
+		}
+	}
+
+	return
+}
+
+// freePort
+//
+// English:
+//
+//  Returns a port free for TCP and UDP, as required by memberlist
+//
+// Português:
+//
+//  Retorna uma porta livre para TCP e UDP, como exigido pela memberlist
+func freePort(address string) (port int, err error) {
+	for attempt := 0; attempt < 10; attempt += 1 {
+		var listener net.Listener
+		listener, err = net.Listen("tcp", net.JoinHostPort(address, "0"))
+		if err != nil {
+			return
+		}
+
+		port = listener.Addr().(*net.TCPAddr).Port
+
+		var packet net.PacketConn
+		packet, err = net.ListenPacket("udp", net.JoinHostPort(address, strconv.Itoa(port)))
+		_ = listener.Close()
+		if err != nil {
+			continue
+		}
+
+		_ = packet.Close()
+		return
+	}
+
+	return
+}
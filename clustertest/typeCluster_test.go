@@ -0,0 +1,359 @@
+package clustertest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	demo "github.com/helmutkemper/iotmaker.docker.builder.demo"
+)
+
+const (
+	//kTestTimeout
+	//
+	// English:
+	//
+	// Time given to the cluster to converge in the tests, long enough for the race detector.
+	//
+	// Português:
+	//
+	// Tempo dado ao cluster para convergir nos testes, longo o suficiente para o detector de corrida.
+	kTestTimeout = 20 * time.Second
+)
+
+// newTestCluster
+//
+// English:
+//
+//  Returns a converged cluster, closed at the end of the test
+//
+// Português:
+//
+//  Retorna um cluster convergido, fechado no fim do teste
+func newTestCluster(t *testing.T, options Options) (cluster *Cluster) {
+	t.Helper()
+
+	var err error
+	cluster, err = New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cluster.Close() })
+
+	err = cluster.WaitForConvergence(kTestTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// waitFor
+//
+// English:
+//
+//  Fails the test when the condition is not satisfied before kTestTimeout
+//
+// Português:
+//
+//  Falha o teste quando a condição não é satisfeita antes de kTestTimeout
+func waitFor(t *testing.T, cluster *Cluster, description string, condition func() bool) {
+	t.Helper()
+
+	if err := cluster.WaitFor(kTestTimeout, condition); err != nil {
+		t.Fatalf("%v: %v", description, err)
+	}
+}
+
+// allRunning
+//
+// English:
+//
+//  Returns true if the condition is true on all running nodes
+//
+// Português:
+//
+//  Retorna true se a condição é verdadeira em todos os nodes rodando
+func allRunning(cluster *Cluster, condition func(server *demo.Server) bool) bool {
+	for _, index := range cluster.Running() {
+		if condition(cluster.Node(index)) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestClusterMembership(t *testing.T) {
+	var cluster = newTestCluster(t, Options{Nodes: 4})
+
+	var tests = []struct {
+		name   string
+		action func() error
+	}{
+		{name: "kill", action: func() error { return cluster.KillNode(1) }},
+		{name: "graceful stop", action: func() error { return cluster.StopNode(context.Background(), 2) }},
+		{name: "restart killed", action: func() error { return cluster.RestartNode(1) }},
+		{name: "restart stopped", action: func() error { return cluster.RestartNode(2) }},
+		{name: "add", action: func() (err error) { _, err = cluster.AddNode(); return }},
+	}
+
+	for _, test := range tests {
+		if err := test.action(); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+
+		if err := cluster.WaitForConvergence(kTestTimeout); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+	}
+
+	if len(cluster.Running()) != 5 {
+		t.Fatalf("running nodes = %v, want 5", cluster.Running())
+	}
+}
+
+func TestClusterKeyValue(t *testing.T) {
+	var cluster = newTestCluster(t, Options{Nodes: 3})
+
+	cluster.Node(0).Set("k", []byte("first"))
+	cluster.Node(2).Set("other", []byte("value"))
+	waitFor(t, cluster, "set", func() bool {
+		return allRunning(cluster, func(server *demo.Server) bool {
+			var value, found = server.Get("k")
+			var other, otherFound = server.Get("other")
+			return found == true && string(value) == "first" && otherFound == true && string(other) == "value"
+		})
+	})
+
+	cluster.Node(1).Set("k", []byte("second"))
+	cluster.Node(2).Delete("other")
+	waitFor(t, cluster, "overwrite and delete", func() bool {
+		return allRunning(cluster, func(server *demo.Server) bool {
+			var value, _ = server.Get("k")
+			var _, otherFound = server.Get("other")
+			return string(value) == "second" && otherFound == false
+		})
+	})
+
+	// English: a restarted node receives the state by push/pull
+	// Português: um node reiniciado recebe o estado pelo push/pull
+	if err := cluster.KillNode(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.RestartNode(2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, cluster, "restart", func() bool {
+		var value, _ = cluster.Node(2).Get("k")
+		return string(value) == "second"
+	})
+}
+
+func TestClusterCounter(t *testing.T) {
+	var cluster = newTestCluster(t, Options{Nodes: 3})
+
+	for i := 0; i != 10; i += 1 {
+		for _, index := range cluster.Running() {
+			cluster.Node(index).GCounter("hits").Inc(1)
+			cluster.Node(index).PNCounter("stock").Dec(2)
+		}
+	}
+
+	var total = func(hits uint64, stock int64) func() bool {
+		return func() bool {
+			return allRunning(cluster, func(server *demo.Server) bool {
+				return server.GCounter("hits").Value() == hits && server.PNCounter("stock").Value() == stock
+			})
+		}
+	}
+	waitFor(t, cluster, "increments", total(30, -60))
+
+	// English: the restarted node keeps its slot and adds to the value of its previous process
+	// Português: o node reiniciado mantém a sua posição e soma ao valor do seu processo anterior
+	if err := cluster.KillNode(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.RestartNode(1); err != nil {
+		t.Fatal(err)
+	}
+	cluster.Node(1).GCounter("hits").Inc(5)
+	cluster.Node(1).PNCounter("stock").Inc(100)
+	waitFor(t, cluster, "restart", total(35, 40))
+}
+
+func TestClusterLeader(t *testing.T) {
+	var cluster = newTestCluster(t, Options{
+		Nodes: 3,
+		Configure: func(index int, server *demo.Server) {
+			server.SetLeaderPriority(index)
+			server.SetLeaderGracePeriod(200 * time.Millisecond)
+		},
+	})
+
+	var agree = func(leader string) func() bool {
+		return func() bool {
+			return allRunning(cluster, func(server *demo.Server) bool {
+				return server.Leader() == leader && server.IsLeader() == (server.LocalName() == leader)
+			})
+		}
+	}
+	waitFor(t, cluster, "election", agree(cluster.Name(2)))
+
+	if err := cluster.StopNode(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, cluster, "leader left", agree(cluster.Name(1)))
+
+	cluster.Node(0).SetLeaderPriority(10)
+	waitFor(t, cluster, "priority change", agree(cluster.Name(0)))
+}
+
+func TestClusterRing(t *testing.T) {
+	var cluster = newTestCluster(t, Options{Nodes: 4})
+
+	var keys = []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var agree = func() bool {
+		var owners = make(map[string]string)
+		return allRunning(cluster, func(server *demo.Server) bool {
+			for _, key := range keys {
+				var nodes = strings.Join(server.Owners(key, 2), ",")
+				if len(server.Owners(key, 2)) != 2 {
+					return false
+				}
+
+				if previous, found := owners[key]; found == true && previous != nodes {
+					return false
+				}
+				owners[key] = nodes
+			}
+			return true
+		})
+	}
+	waitFor(t, cluster, "owners", agree)
+
+	if err := cluster.KillNode(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.WaitForConvergence(kTestTimeout); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, cluster, "owners after a failure", func() bool {
+		return agree() == true && allRunning(cluster, func(server *demo.Server) bool {
+			for _, key := range keys {
+				for _, owner := range server.Owners(key, 4) {
+					if owner == cluster.Name(3) {
+						return false
+					}
+				}
+			}
+			return true
+		})
+	})
+}
+
+func TestClusterKeyring(t *testing.T) {
+	var oldKey = bytes.Repeat([]byte{1}, 16)
+	var newKey = bytes.Repeat([]byte{2}, 32)
+
+	var cluster = newTestCluster(t, Options{
+		Nodes: 3,
+		Configure: func(index int, server *demo.Server) {
+			if err := server.SetEncryptionKey(oldKey); err != nil {
+				t.Error(err)
+			}
+		},
+	})
+
+	var ctx, cancel = context.WithTimeout(context.Background(), kTestTimeout)
+	defer cancel()
+
+	var steps = []struct {
+		name string
+		step func(ctx context.Context, key []byte) (map[string]error, error)
+		key  []byte
+	}{
+		{name: "install", step: cluster.Node(0).InstallKey, key: newKey},
+		{name: "use", step: cluster.Node(1).UseKey, key: newKey},
+		{name: "remove", step: cluster.Node(2).RemoveKey, key: oldKey},
+	}
+
+	for _, step := range steps {
+		var results, err = step.step(ctx, step.key)
+		if err != nil {
+			t.Fatalf("%v: %v %v", step.name, err, results)
+		}
+
+		if len(results) != 3 {
+			t.Fatalf("%v: answers of %v, want 3 nodes", step.name, results)
+		}
+	}
+
+	if _, err := cluster.Node(0).RemoveKey(ctx, newKey); err == nil {
+		t.Fatal("the primary key was removed")
+	}
+
+	cluster.Node(0).Set("after", []byte("rotation"))
+	waitFor(t, cluster, "gossip with the new key", func() bool {
+		return allRunning(cluster, func(server *demo.Server) bool {
+			var value, _ = server.Get("after")
+			return string(value) == "rotation"
+		})
+	})
+}
+
+func TestClusterCallAndQuery(t *testing.T) {
+	var cluster = newTestCluster(t, Options{
+		Nodes: 3,
+		Configure: func(index int, server *demo.Server) {
+			_ = server.Handle("echo", func(ctx context.Context, from string, payload []byte) ([]byte, error) {
+				return append([]byte(server.LocalName()+":"), payload...), nil
+			})
+			_ = server.HandleQuery("who", func(ctx context.Context, from string, payload []byte) ([]byte, error) {
+				return []byte(server.LocalName()), nil
+			})
+		},
+	})
+
+	var ctx, cancel = context.WithTimeout(context.Background(), kTestTimeout)
+	defer cancel()
+
+	var replay, err = cluster.Node(0).Call(ctx, cluster.Name(2), "echo", []byte("hi"))
+	if err != nil || string(replay) != cluster.Name(2)+":hi" {
+		t.Fatalf("Call() = %q, %v", replay, err)
+	}
+
+	var tests = []struct {
+		name    string
+		query   string
+		answers int
+		errors  int
+	}{
+		{name: "query handler", query: "who", answers: 3},
+		{name: "call handler is not a query", query: "echo", errors: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var query, err = cluster.Node(0).Query(ctx, test.query, nil, demo.QueryParams{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var answers, errors = 0, 0
+			for response := range query.Responses() {
+				if response.Err != nil {
+					errors += 1
+					continue
+				}
+				answers += 1
+			}
+
+			if answers != test.answers || errors != test.errors {
+				t.Fatalf("answers = %d, errors = %d, want %d, %d", answers, errors, test.answers, test.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package clustertest
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// FakeAnswer
+//
+// English:
+//
+//  Answer of FakeResolver for one lookup
+//
+//   Fields:
+//     Addresses: IP addresses returned by LookupIP()
+//     Records: SRV records returned by LookupSRV()
+//     NotFound: returns NXDOMAIN, a *net.DNSError with IsNotFound
+//     Timeout: waits for the deadline of the context, when there is one, and returns a
+//              *net.DNSError with IsTimeout
+//     Err: error returned as is
+//
+// Português:
+//
+//  Resposta do FakeResolver para uma consulta
+//
+//   Campos:
+//     Addresses: endereços IP retornados por LookupIP()
+//     Records: registros SRV retornados por LookupSRV()
+//     NotFound: retorna NXDOMAIN, um *net.DNSError com IsNotFound
+//     Timeout: espera o prazo do contexto, quando existe, e retorna um *net.DNSError com IsTimeout
+//     Err: erro retornado como está
+type FakeAnswer struct {
+	Addresses []string
+	Records   []net.SRV
+	NotFound  bool
+	Timeout   bool
+	Err       error
+}
+
+// FakeResolver
+//
+// English:
+//
+//  Resolver for tests, whose answers are scripted by host. Unknown hosts return NXDOMAIN.
+//
+//   Example:
+//     var resolver = &FakeResolver{}
+//     resolver.SetAnswer("delete_after_test_instance_0", "10.0.0.2", "10.0.0.3")
+//     resolver.Queue("delete_after_test_instance_1", FakeAnswer{Timeout: true}, FakeAnswer{Addresses: []string{"10.0.0.4"}})
+//     server.SetResolver(resolver)
+//
+//   Note:
+//     * The Cluster uses a FakeResolver to publish the nodes, see Cluster.Resolver().
+//
+// Português:
+//
+//  Resolvedor para testes, cujas respostas são programadas por host. Hosts desconhecidos retornam
+//  NXDOMAIN.
+//
+//   Exemplo:
+//     var resolver = &FakeResolver{}
+//     resolver.SetAnswer("delete_after_test_instance_0", "10.0.0.2", "10.0.0.3")
+//     resolver.Queue("delete_after_test_instance_1", FakeAnswer{Timeout: true}, FakeAnswer{Addresses: []string{"10.0.0.4"}})
+//     server.SetResolver(resolver)
+//
+//   Nota:
+//     * O Cluster usa um FakeResolver para publicar os nodes, veja Cluster.Resolver().
+type FakeResolver struct {
+	mutex   sync.Mutex
+	answers map[string][]FakeAnswer
+	lookups map[string]int
+}
+
+// Queue
+//
+// English:
+//
+//  Defines the answers of the next lookups of the host, one answer per lookup. The last answer is
+//  repeated for the following lookups
+//
+//   Input:
+//     host: name of the service/container
+//     answers: answers in the order of the lookups
+//
+// Português:
+//
+//  Define as respostas das próximas consultas do host, uma resposta por consulta. A última resposta
+//  é repetida para as consultas seguintes
+//
+//   Entrada:
+//     host: nome do serviço/container
+//     answers: respostas na ordem das consultas
+func (e *FakeResolver) Queue(host string, answers ...FakeAnswer) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.answers == nil {
+		e.answers = make(map[string][]FakeAnswer)
+	}
+
+	e.answers[host] = append([]FakeAnswer{}, answers...)
+}
+
+// SetAnswer
+//
+// English:
+//
+//  Makes all the next lookups of the host return the addresses
+//
+// Português:
+//
+//  Faz todas as próximas consultas do host retornarem os endereços
+func (e *FakeResolver) SetAnswer(host string, addresses ...string) {
+	e.Queue(host, FakeAnswer{Addresses: addresses})
+}
+
+// SetSRV
+//
+// English:
+//
+//  Makes all the next SRV lookups of the name return the records
+//
+//   Input:
+//     name: name looked up, _service._proto.name or, when service and proto are empty, name
+//     records: SRV records
+//
+// Português:
+//
+//  Faz todas as próximas consultas SRV do nome retornarem os registros
+//
+//   Entrada:
+//     name: nome consultado, _service._proto.name ou, quando service e proto são vazios, name
+//     records: registros SRV
+func (e *FakeResolver) SetSRV(name string, records ...net.SRV) {
+	e.Queue(name, FakeAnswer{Records: records})
+}
+
+// SetNotFound
+//
+// English:
+//
+//  Makes all the next lookups of the host return NXDOMAIN
+//
+// Português:
+//
+//  Faz todas as próximas consultas do host retornarem NXDOMAIN
+func (e *FakeResolver) SetNotFound(host string) {
+	e.Queue(host, FakeAnswer{NotFound: true})
+}
+
+// SetTimeout
+//
+// English:
+//
+//  Makes all the next lookups of the host wait for the deadline of the context and fail with a
+//  timeout
+//
+// Português:
+//
+//  Faz todas as próximas consultas do host esperarem o prazo do contexto e falharem por tempo limite
+func (e *FakeResolver) SetTimeout(host string) {
+	e.Queue(host, FakeAnswer{Timeout: true})
+}
+
+// Lookups
+//
+// English:
+//
+//  Returns the number of lookups of the host
+//
+// Português:
+//
+//  Retorna o número de consultas do host
+func (e *FakeResolver) Lookups(host string) (count int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.lookups[host]
+}
+
+// LookupIP
+//
+// English:
+//
+//  Implements Resolver with the scripted answer of the host
+//
+// Português:
+//
+//  Implementa Resolver com a resposta programada do host
+func (e *FakeResolver) LookupIP(ctx context.Context, host string) (addresses []net.IP, err error) {
+	var answer = e.next(host)
+
+	err = answer.error(ctx, host)
+	if err != nil {
+		return
+	}
+
+	addresses = make([]net.IP, 0, len(answer.Addresses))
+	for _, address := range answer.Addresses {
+		if ip := net.ParseIP(address); ip != nil {
+			addresses = append(addresses, ip)
+		}
+	}
+
+	return
+}
+
+// LookupSRV
+//
+// English:
+//
+//  Implements Resolver with the scripted answer of the name _service._proto.name or, when service and
+//  proto are empty, of name
+//
+// Português:
+//
+//  Implementa Resolver com a resposta programada do nome _service._proto.name ou, quando service e
+//  proto são vazios, de name
+func (e *FakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (records []*net.SRV, err error) {
+	var host = srvName(service, proto, name)
+	var answer = e.next(host)
+
+	err = answer.error(ctx, host)
+	if err != nil {
+		return
+	}
+
+	records = make([]*net.SRV, 0, len(answer.Records))
+	for _, record := range answer.Records {
+		var value = record
+		records = append(records, &value)
+	}
+
+	return
+}
+
+// error
+//
+// English:
+//
+//  Returns the scripted error of the answer, waiting for the deadline of the context on a timeout
+//
+// Português:
+//
+//  Retorna o erro programado da resposta, esperando o prazo do contexto em um tempo limite
+func (e FakeAnswer) error(ctx context.Context, host string) (err error) {
+	switch {
+	case e.Err != nil:
+		err = e.Err
+
+	case e.Timeout == true:
+		if _, found := ctx.Deadline(); found == true {
+			<-ctx.Done()
+		}
+		err = &net.DNSError{Err: "i/o timeout", Name: host, IsTimeout: true, IsTemporary: true}
+
+	case e.NotFound == true:
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return
+}
+
+// next
+//
+// English:
+//
+//  Counts the lookup and returns the answer of the host, advancing the queue
+//
+// Português:
+//
+//  Conta a consulta e retorna a resposta do host, avançando a fila
+func (e *FakeResolver) next(host string) (answer FakeAnswer) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.lookups == nil {
+		e.lookups = make(map[string]int)
+	}
+	e.lookups[host] += 1
+
+	var answers = e.answers[host]
+	if len(answers) == 0 {
+		answer.NotFound = true
+		return
+	}
+
+	answer = answers[0]
+	if len(answers) > 1 {
+		e.answers[host] = answers[1:]
+	}
+
+	return
+}
+
+// srvName
+//
+// English:
+//
+//  Returns the name looked up for the SRV records, _service._proto.name or, when service and proto
+//  are empty, name, the same rule of net.LookupSRV()
+//
+// Português:
+//
+//  Retorna o nome consultado para os registros SRV, _service._proto.name ou, quando service e proto
+//  são vazios, name, a mesma regra de net.LookupSRV()
+func srvName(service, proto, name string) string {
+	if service == "" && proto == "" {
+		return name
+	}
+
+	return "_" + service + "._" + proto + "." + name
+}
@@ -3,15 +3,13 @@ module github.com/helmutkemper/iotmaker.docker.builder.demo
 go 1.17
 
 require (
-	github.com/hashicorp/logutils v1.0.0
+	github.com/armon/go-metrics v0.0.0-20180917152333-f0300d1749da
 	github.com/hashicorp/memberlist v0.3.0
-	github.com/helmutkemper/util v0.0.0-20210420213725-d4fad0e09c93
 	google.golang.org/grpc v1.43.0
 	google.golang.org/protobuf v1.27.1
 )
 
 require (
-	github.com/armon/go-metrics v0.0.0-20180917152333-f0300d1749da // indirect
 	github.com/golang/protobuf v1.5.0 // indirect
 	github.com/google/btree v0.0.0-20180813153112-4030bb1f1f0c // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.26.0
+// 	protoc-gen-go v1.27.1
 // 	protoc        v3.17.3
 // source: typeGrpc.proto
 
@@ -65,7 +65,9 @@ type InstanceIsReadyReplay struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	IsReady bool `protobuf:"varint,1,opt,name=IsReady,proto3" json:"IsReady,omitempty"`
+	IsReady      bool     `protobuf:"varint,1,opt,name=IsReady,proto3" json:"IsReady,omitempty"`
+	IsLive       bool     `protobuf:"varint,2,opt,name=IsLive,proto3" json:"IsLive,omitempty"`
+	FailedChecks []string `protobuf:"bytes,3,rep,name=FailedChecks,proto3" json:"FailedChecks,omitempty"`
 }
 
 func (x *InstanceIsReadyReplay) Reset() {
@@ -107,31 +109,471 @@ func (x *InstanceIsReadyReplay) GetIsReady() bool {
 	return false
 }
 
+func (x *InstanceIsReadyReplay) GetIsLive() bool {
+	if x != nil {
+		return x.IsLive
+	}
+	return false
+}
+
+func (x *InstanceIsReadyReplay) GetFailedChecks() []string {
+	if x != nil {
+		return x.FailedChecks
+	}
+	return nil
+}
+
+type CommunicationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From    string `protobuf:"bytes,1,opt,name=From,proto3" json:"From,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	Method  string `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
+}
+
+func (x *CommunicationRequest) Reset() {
+	*x = CommunicationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommunicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommunicationRequest) ProtoMessage() {}
+
+func (x *CommunicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommunicationRequest.ProtoReflect.Descriptor instead.
+func (*CommunicationRequest) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommunicationRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *CommunicationRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *CommunicationRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type CommunicationReplay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payload []byte `protobuf:"bytes,1,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *CommunicationReplay) Reset() {
+	*x = CommunicationReplay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommunicationReplay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommunicationReplay) ProtoMessage() {}
+
+func (x *CommunicationReplay) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommunicationReplay.ProtoReflect.Descriptor instead.
+func (*CommunicationReplay) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommunicationReplay) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type PubSubRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node      string   `protobuf:"bytes,1,opt,name=Node,proto3" json:"Node,omitempty"`
+	Subscribe bool     `protobuf:"varint,2,opt,name=Subscribe,proto3" json:"Subscribe,omitempty"`
+	Topics    []string `protobuf:"bytes,3,rep,name=Topics,proto3" json:"Topics,omitempty"`
+	Ack       uint64   `protobuf:"varint,4,opt,name=Ack,proto3" json:"Ack,omitempty"`
+}
+
+func (x *PubSubRequest) Reset() {
+	*x = PubSubRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PubSubRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PubSubRequest) ProtoMessage() {}
+
+func (x *PubSubRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PubSubRequest.ProtoReflect.Descriptor instead.
+func (*PubSubRequest) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PubSubRequest) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *PubSubRequest) GetSubscribe() bool {
+	if x != nil {
+		return x.Subscribe
+	}
+	return false
+}
+
+func (x *PubSubRequest) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *PubSubRequest) GetAck() uint64 {
+	if x != nil {
+		return x.Ack
+	}
+	return 0
+}
+
+type PubSubMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From        string `protobuf:"bytes,1,opt,name=From,proto3" json:"From,omitempty"`
+	Incarnation string `protobuf:"bytes,2,opt,name=Incarnation,proto3" json:"Incarnation,omitempty"`
+	Sequence    uint64 `protobuf:"varint,3,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+	Topic       string `protobuf:"bytes,4,opt,name=Topic,proto3" json:"Topic,omitempty"`
+	Payload     []byte `protobuf:"bytes,5,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *PubSubMessage) Reset() {
+	*x = PubSubMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PubSubMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PubSubMessage) ProtoMessage() {}
+
+func (x *PubSubMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PubSubMessage.ProtoReflect.Descriptor instead.
+func (*PubSubMessage) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PubSubMessage) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *PubSubMessage) GetIncarnation() string {
+	if x != nil {
+		return x.Incarnation
+	}
+	return ""
+}
+
+func (x *PubSubMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PubSubMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *PubSubMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type QueryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From    string `protobuf:"bytes,1,opt,name=From,proto3" json:"From,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Payload []byte `protobuf:"bytes,3,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRequest) ProtoMessage() {}
+
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type QueryReplay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ack     bool   `protobuf:"varint,1,opt,name=Ack,proto3" json:"Ack,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *QueryReplay) Reset() {
+	*x = QueryReplay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_typeGrpc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryReplay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryReplay) ProtoMessage() {}
+
+func (x *QueryReplay) ProtoReflect() protoreflect.Message {
+	mi := &file_typeGrpc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryReplay.ProtoReflect.Descriptor instead.
+func (*QueryReplay) Descriptor() ([]byte, []int) {
+	return file_typeGrpc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueryReplay) GetAck() bool {
+	if x != nil {
+		return x.Ack
+	}
+	return false
+}
+
+func (x *QueryReplay) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
 var File_typeGrpc_proto protoreflect.FileDescriptor
 
 var file_typeGrpc_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x74, 0x79, 0x70, 0x65, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x04, 0x64, 0x65, 0x6d, 0x6f, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
-	0x31, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x73, 0x52, 0x65, 0x61,
+	0x6d, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x73, 0x52, 0x65, 0x61,
 	0x64, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x49, 0x73, 0x52, 0x65,
 	0x61, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49, 0x73, 0x52, 0x65, 0x61,
-	0x64, 0x79, 0x32, 0x8b, 0x01, 0x0a, 0x0d, 0x53, 0x79, 0x6e, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x73, 0x12, 0x45, 0x0a, 0x17, 0x67, 0x72, 0x70, 0x63, 0x46, 0x75, 0x6e, 0x63,
-	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12,
-	0x0b, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x64,
-	0x65, 0x6d, 0x6f, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x73, 0x52, 0x65,
-	0x61, 0x64, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x15, 0x67,
-	0x72, 0x70, 0x63, 0x46, 0x75, 0x6e, 0x63, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x0b, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
-	0x42, 0x73, 0x0a, 0x24, 0x69, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x69, 0x6f, 0x74, 0x6d,
-	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x62, 0x75, 0x69, 0x6c,
-	0x64, 0x65, 0x72, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x42, 0x09, 0x67, 0x72, 0x70, 0x63, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x68, 0x65, 0x6c, 0x6d, 0x75, 0x74, 0x6b, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x2f, 0x69,
-	0x6f, 0x74, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x62,
-	0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x3b, 0x67, 0x72, 0x70, 0x63,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x49, 0x73, 0x4c, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x49, 0x73, 0x4c, 0x69, 0x76, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x46, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x22, 0x5c,
+	0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x2f, 0x0a, 0x13,
+	0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x6b, 0x0a,
+	0x0d, 0x50, 0x75, 0x62, 0x53, 0x75, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x4e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x6f,
+	0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x06, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x41, 0x63, 0x6b, 0x22, 0x91, 0x01, 0x0a, 0x0d, 0x50,
+	0x75, 0x62, 0x53, 0x75, 0x62, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d,
+	0x12, 0x20, 0x0a, 0x0b, 0x49, 0x6e, 0x63, 0x61, 0x72, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x49, 0x6e, 0x63, 0x61, 0x72, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54,
+	0x6f, 0x70, 0x69, 0x63, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x50,
+	0x0a, 0x0c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x22, 0x39, 0x0a, 0x0b, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x41, 0x63,
+	0x6b, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0xa6, 0x02, 0x0a, 0x0d,
+	0x53, 0x79, 0x6e, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x45, 0x0a,
+	0x17, 0x67, 0x72, 0x70, 0x63, 0x46, 0x75, 0x6e, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x49, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x0b, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x15, 0x67, 0x72, 0x70, 0x63, 0x46, 0x75, 0x6e, 0x63,
+	0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e,
+	0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x65, 0x6d, 0x6f,
+	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x70, 0x6c, 0x61, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0e, 0x67, 0x72, 0x70, 0x63, 0x46, 0x75,
+	0x6e, 0x63, 0x50, 0x75, 0x62, 0x53, 0x75, 0x62, 0x12, 0x13, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x2e,
+	0x50, 0x75, 0x62, 0x53, 0x75, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x50, 0x75, 0x62, 0x53, 0x75, 0x62, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x3a, 0x0a, 0x0d, 0x67, 0x72, 0x70, 0x63,
+	0x46, 0x75, 0x6e, 0x63, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x2e, 0x64, 0x65, 0x6d, 0x6f,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e,
+	0x64, 0x65, 0x6d, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79,
+	0x22, 0x00, 0x30, 0x01, 0x42, 0x73, 0x0a, 0x24, 0x69, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x69, 0x6f, 0x74, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x42, 0x09, 0x67, 0x72,
+	0x70, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x65, 0x6c, 0x6d, 0x75, 0x74, 0x6b, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x2f, 0x69, 0x6f, 0x74, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x6f, 0x63, 0x6b,
+	0x65, 0x72, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x2e, 0x64, 0x65, 0x6d, 0x6f, 0x3b,
+	0x67, 0x72, 0x70, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -146,18 +588,28 @@ func file_typeGrpc_proto_rawDescGZIP() []byte {
 	return file_typeGrpc_proto_rawDescData
 }
 
-var file_typeGrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_typeGrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_typeGrpc_proto_goTypes = []interface{}{
 	(*Empty)(nil),                 // 0: demo.Empty
 	(*InstanceIsReadyReplay)(nil), // 1: demo.InstanceIsReadyReplay
+	(*CommunicationRequest)(nil),  // 2: demo.CommunicationRequest
+	(*CommunicationReplay)(nil),   // 3: demo.CommunicationReplay
+	(*PubSubRequest)(nil),         // 4: demo.PubSubRequest
+	(*PubSubMessage)(nil),         // 5: demo.PubSubMessage
+	(*QueryRequest)(nil),          // 6: demo.QueryRequest
+	(*QueryReplay)(nil),           // 7: demo.QueryReplay
 }
 var file_typeGrpc_proto_depIdxs = []int32{
 	0, // 0: demo.SyncInstances.grpcFuncInstanceIsReady:input_type -> demo.Empty
-	0, // 1: demo.SyncInstances.grpcFuncCommunication:input_type -> demo.Empty
-	1, // 2: demo.SyncInstances.grpcFuncInstanceIsReady:output_type -> demo.InstanceIsReadyReplay
-	0, // 3: demo.SyncInstances.grpcFuncCommunication:output_type -> demo.Empty
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	2, // 1: demo.SyncInstances.grpcFuncCommunication:input_type -> demo.CommunicationRequest
+	4, // 2: demo.SyncInstances.grpcFuncPubSub:input_type -> demo.PubSubRequest
+	6, // 3: demo.SyncInstances.grpcFuncQuery:input_type -> demo.QueryRequest
+	1, // 4: demo.SyncInstances.grpcFuncInstanceIsReady:output_type -> demo.InstanceIsReadyReplay
+	3, // 5: demo.SyncInstances.grpcFuncCommunication:output_type -> demo.CommunicationReplay
+	5, // 6: demo.SyncInstances.grpcFuncPubSub:output_type -> demo.PubSubMessage
+	7, // 7: demo.SyncInstances.grpcFuncQuery:output_type -> demo.QueryReplay
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -193,6 +645,78 @@ func file_typeGrpc_proto_init() {
 				return nil
 			}
 		}
+		file_typeGrpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommunicationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_typeGrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommunicationReplay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_typeGrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PubSubRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_typeGrpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PubSubMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_typeGrpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_typeGrpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryReplay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -200,7 +724,7 @@ func file_typeGrpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_typeGrpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
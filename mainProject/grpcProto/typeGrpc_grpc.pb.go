@@ -19,7 +19,9 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SyncInstancesClient interface {
 	GrpcFuncInstanceIsReady(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InstanceIsReadyReplay, error)
-	GrpcFuncCommunication(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	GrpcFuncCommunication(ctx context.Context, in *CommunicationRequest, opts ...grpc.CallOption) (*CommunicationReplay, error)
+	GrpcFuncPubSub(ctx context.Context, opts ...grpc.CallOption) (SyncInstances_GrpcFuncPubSubClient, error)
+	GrpcFuncQuery(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (SyncInstances_GrpcFuncQueryClient, error)
 }
 
 type syncInstancesClient struct {
@@ -39,8 +41,8 @@ func (c *syncInstancesClient) GrpcFuncInstanceIsReady(ctx context.Context, in *E
 	return out, nil
 }
 
-func (c *syncInstancesClient) GrpcFuncCommunication(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *syncInstancesClient) GrpcFuncCommunication(ctx context.Context, in *CommunicationRequest, opts ...grpc.CallOption) (*CommunicationReplay, error) {
+	out := new(CommunicationReplay)
 	err := c.cc.Invoke(ctx, "/demo.SyncInstances/grpcFuncCommunication", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -48,12 +50,77 @@ func (c *syncInstancesClient) GrpcFuncCommunication(ctx context.Context, in *Emp
 	return out, nil
 }
 
+func (c *syncInstancesClient) GrpcFuncPubSub(ctx context.Context, opts ...grpc.CallOption) (SyncInstances_GrpcFuncPubSubClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SyncInstances_ServiceDesc.Streams[0], "/demo.SyncInstances/grpcFuncPubSub", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &syncInstancesGrpcFuncPubSubClient{stream}
+	return x, nil
+}
+
+type SyncInstances_GrpcFuncPubSubClient interface {
+	Send(*PubSubRequest) error
+	Recv() (*PubSubMessage, error)
+	grpc.ClientStream
+}
+
+type syncInstancesGrpcFuncPubSubClient struct {
+	grpc.ClientStream
+}
+
+func (x *syncInstancesGrpcFuncPubSubClient) Send(m *PubSubRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *syncInstancesGrpcFuncPubSubClient) Recv() (*PubSubMessage, error) {
+	m := new(PubSubMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *syncInstancesClient) GrpcFuncQuery(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (SyncInstances_GrpcFuncQueryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SyncInstances_ServiceDesc.Streams[1], "/demo.SyncInstances/grpcFuncQuery", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &syncInstancesGrpcFuncQueryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SyncInstances_GrpcFuncQueryClient interface {
+	Recv() (*QueryReplay, error)
+	grpc.ClientStream
+}
+
+type syncInstancesGrpcFuncQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *syncInstancesGrpcFuncQueryClient) Recv() (*QueryReplay, error) {
+	m := new(QueryReplay)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SyncInstancesServer is the server API for SyncInstances service.
 // All implementations must embed UnimplementedSyncInstancesServer
 // for forward compatibility
 type SyncInstancesServer interface {
 	GrpcFuncInstanceIsReady(context.Context, *Empty) (*InstanceIsReadyReplay, error)
-	GrpcFuncCommunication(context.Context, *Empty) (*Empty, error)
+	GrpcFuncCommunication(context.Context, *CommunicationRequest) (*CommunicationReplay, error)
+	GrpcFuncPubSub(SyncInstances_GrpcFuncPubSubServer) error
+	GrpcFuncQuery(*QueryRequest, SyncInstances_GrpcFuncQueryServer) error
 	mustEmbedUnimplementedSyncInstancesServer()
 }
 
@@ -64,9 +131,15 @@ type UnimplementedSyncInstancesServer struct {
 func (UnimplementedSyncInstancesServer) GrpcFuncInstanceIsReady(context.Context, *Empty) (*InstanceIsReadyReplay, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GrpcFuncInstanceIsReady not implemented")
 }
-func (UnimplementedSyncInstancesServer) GrpcFuncCommunication(context.Context, *Empty) (*Empty, error) {
+func (UnimplementedSyncInstancesServer) GrpcFuncCommunication(context.Context, *CommunicationRequest) (*CommunicationReplay, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GrpcFuncCommunication not implemented")
 }
+func (UnimplementedSyncInstancesServer) GrpcFuncPubSub(SyncInstances_GrpcFuncPubSubServer) error {
+	return status.Errorf(codes.Unimplemented, "method GrpcFuncPubSub not implemented")
+}
+func (UnimplementedSyncInstancesServer) GrpcFuncQuery(*QueryRequest, SyncInstances_GrpcFuncQueryServer) error {
+	return status.Errorf(codes.Unimplemented, "method GrpcFuncQuery not implemented")
+}
 func (UnimplementedSyncInstancesServer) mustEmbedUnimplementedSyncInstancesServer() {}
 
 // UnsafeSyncInstancesServer may be embedded to opt out of forward compatibility for this service.
@@ -99,7 +172,7 @@ func _SyncInstances_GrpcFuncInstanceIsReady_Handler(srv interface{}, ctx context
 }
 
 func _SyncInstances_GrpcFuncCommunication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(CommunicationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -111,11 +184,58 @@ func _SyncInstances_GrpcFuncCommunication_Handler(srv interface{}, ctx context.C
 		FullMethod: "/demo.SyncInstances/grpcFuncCommunication",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SyncInstancesServer).GrpcFuncCommunication(ctx, req.(*Empty))
+		return srv.(SyncInstancesServer).GrpcFuncCommunication(ctx, req.(*CommunicationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SyncInstances_GrpcFuncPubSub_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SyncInstancesServer).GrpcFuncPubSub(&syncInstancesGrpcFuncPubSubServer{stream})
+}
+
+type SyncInstances_GrpcFuncPubSubServer interface {
+	Send(*PubSubMessage) error
+	Recv() (*PubSubRequest, error)
+	grpc.ServerStream
+}
+
+type syncInstancesGrpcFuncPubSubServer struct {
+	grpc.ServerStream
+}
+
+func (x *syncInstancesGrpcFuncPubSubServer) Send(m *PubSubMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *syncInstancesGrpcFuncPubSubServer) Recv() (*PubSubRequest, error) {
+	m := new(PubSubRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _SyncInstances_GrpcFuncQuery_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyncInstancesServer).GrpcFuncQuery(m, &syncInstancesGrpcFuncQueryServer{stream})
+}
+
+type SyncInstances_GrpcFuncQueryServer interface {
+	Send(*QueryReplay) error
+	grpc.ServerStream
+}
+
+type syncInstancesGrpcFuncQueryServer struct {
+	grpc.ServerStream
+}
+
+func (x *syncInstancesGrpcFuncQueryServer) Send(m *QueryReplay) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // SyncInstances_ServiceDesc is the grpc.ServiceDesc for SyncInstances service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -132,6 +252,18 @@ var SyncInstances_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _SyncInstances_GrpcFuncCommunication_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "grpcFuncPubSub",
+			Handler:       _SyncInstances_GrpcFuncPubSub_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "grpcFuncQuery",
+			Handler:       _SyncInstances_GrpcFuncQuery_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "typeGrpc.proto",
 }
@@ -1,33 +1,35 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"github.com/hashicorp/logutils"
 	demo "github.com/helmutkemper/iotmaker.docker.builder.demo"
 	"github.com/helmutkemper/util"
 	"log"
 	"os"
-	"sync"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
 func main() {
 	var err error
-	var counter = 0.0
 
 	var server = &demo.Server{}
+	server.SetLogger(demo.NewLogger(os.Stderr, demo.LogLevelWarn, demo.LogEncoderText))
+
+	// English: the ID is kept in the file system of the container, so the instance keeps its ID after
+	// "restart-me!". NODE_ID, when defined, has priority over the file
+	//
+	// Português: o ID é guardado no sistema de arquivos do container, assim a instância mantém o seu ID
+	// depois de "restart-me!". NODE_ID, quando definida, tem prioridade sobre o arquivo
+	server.SetNodeIDEnv("NODE_ID")
+	server.SetNodeIDPath("/var/lib/iotmaker/node.id")
 	err = server.Init(1010, "delete_after_test_instance_0")
 	if err != nil {
 		log.Printf("error: %v", err)
 	}
 
-	filter := &logutils.LevelFilter{
-		Levels:   []logutils.LogLevel{"DEBUG", "WARN", "ERROR"},
-		MinLevel: logutils.LogLevel("WARN"),
-		Writer:   os.Stderr,
-	}
-	log.SetOutput(filter)
-
 	timer := time.NewTimer(5 * time.Second)
 	go func() {
 		<-timer.C
@@ -47,18 +49,37 @@ func main() {
 		log.Println("bug: o código não poderia ter entrado aqui")
 	}()
 
+	// English: every instance counts its ticks in the same cluster counter and prints the total of the
+	// cluster, so all instances converge to the same value
+	//
+	// Português: cada instância conta os seus ticks no mesmo contador do cluster e imprime o total do
+	// cluster, assim todas as instâncias convergem para o mesmo valor
+	var counter = server.GCounter("counter")
 	ticker := time.NewTicker(1000 * time.Millisecond)
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				fmt.Printf("counter: %v\n", counter)
-				counter += 1.0
+				counter.Inc(1)
+				fmt.Printf("counter: %v\n", counter.Value())
 			}
 		}
 	}()
 
-	var wg = &sync.WaitGroup{}
-	wg.Add(1)
-	wg.Wait()
+	// English: leaves the cluster on SIGTERM, so the other instances don't wait for the failure detector
+	//
+	// Português: sai do cluster no SIGTERM, assim as demais instâncias não esperam pelo detector de falhas
+	var signalChan = make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGTERM, os.Interrupt)
+	<-signalChan
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var acknowledged bool
+	acknowledged, err = server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("error: %v", err)
+	}
+	log.Printf("leave acknowledged: %v", acknowledged)
 }
@@ -0,0 +1,183 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"net"
+	"strings"
+)
+
+// AddressFamily
+//
+// English:
+//
+//  Family of an IP address
+//
+// Português:
+//
+//  Família de um endereço IP
+type AddressFamily int
+
+const (
+	// AddressFamilyAny
+	//
+	// English: no preference, or an address that is not an IP
+	//
+	// Português: sem preferência, ou um endereço que não é um IP
+	AddressFamilyAny AddressFamily = iota
+
+	// AddressFamilyIPv4
+	//
+	// English: IPv4 address, including the IPv4-mapped IPv6 addresses
+	//
+	// Português: endereço IPv4, incluindo os endereços IPv6 mapeados de IPv4
+	AddressFamilyIPv4
+
+	// AddressFamilyIPv6
+	//
+	// English: IPv6 address
+	//
+	// Português: endereço IPv6
+	AddressFamilyIPv6
+)
+
+// String
+//
+// English:
+//
+//  Returns the name of the family
+//
+// Português:
+//
+//  Retorna o nome da família
+func (e AddressFamily) String() string {
+	switch e {
+	case AddressFamilyIPv4:
+		return "ipv4"
+	case AddressFamilyIPv6:
+		return "ipv6"
+	}
+
+	return "any"
+}
+
+// AddressFamilyOf
+//
+// English:
+//
+//  Returns the family of the address
+//
+//   Input:
+//     address: IP address, with or without port, for example "10.0.0.2", "[fd00::2]:7946" or "fd00::2"
+//
+//   Output:
+//     family: AddressFamilyIPv4, AddressFamilyIPv6 or, when the address is not an IP, AddressFamilyAny
+//
+// Português:
+//
+//  Retorna a família do endereço
+//
+//   Entrada:
+//     address: endereço IP, com ou sem porta, por exemplo "10.0.0.2", "[fd00::2]:7946" ou "fd00::2"
+//
+//   Saída:
+//     family: AddressFamilyIPv4, AddressFamilyIPv6 ou, quando o endereço não é um IP, AddressFamilyAny
+func AddressFamilyOf(address string) (family AddressFamily) {
+	return addressFamilyOfIP(net.ParseIP(hostOf(address)))
+}
+
+// addressFamilyOfIP
+//
+// English:
+//
+//  Returns the family of the IP, AddressFamilyAny for nil
+//
+// Português:
+//
+//  Retorna a família do IP, AddressFamilyAny para nil
+func addressFamilyOfIP(ip net.IP) (family AddressFamily) {
+	switch {
+	case ip == nil:
+		return AddressFamilyAny
+	case ip.To4() != nil:
+		return AddressFamilyIPv4
+	}
+
+	return AddressFamilyIPv6
+}
+
+// hostOf
+//
+// English:
+//
+//  Returns the host of an address with or without port, without the brackets of IPv6
+//
+// Português:
+//
+//  Retorna o host de um endereço com ou sem porta, sem os colchetes do IPv6
+func hostOf(address string) (host string) {
+	var err error
+	host, _, err = net.SplitHostPort(address)
+	if err != nil {
+		host = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+	}
+
+	return
+}
+
+// preferAddressFamily
+//
+// English:
+//
+//  Keeps only the addresses of the preferred family, when there is at least one of them
+//
+// Português:
+//
+//  Mantém apenas os endereços da família preferida, quando existe pelo menos um deles
+func preferAddressFamily(addresses []net.IP, family AddressFamily) (preferred []net.IP) {
+	if family == AddressFamilyAny {
+		return addresses
+	}
+
+	preferred = make([]net.IP, 0, len(addresses))
+	for _, ip := range addresses {
+		if addressFamilyOfIP(ip) == family {
+			preferred = append(preferred, ip)
+		}
+	}
+
+	if len(preferred) == 0 {
+		return addresses
+	}
+
+	return
+}
+
+// interfaceAddress
+//
+// English:
+//
+//  Returns the first global unicast address of the family found in the network interfaces, used as
+//  the advertise address when the node listens on all IPv6 addresses
+//
+// Português:
+//
+//  Retorna o primeiro endereço global unicast da família encontrado nas interfaces de rede, usado como
+//  endereço anunciado quando o node escuta em todos os endereços IPv6
+func interfaceAddress(family AddressFamily) (address string) {
+	var addresses, err = net.InterfaceAddrs()
+	if err != nil {
+		return
+	}
+
+	for _, interfaceAddress := range addresses {
+		var network, ok = interfaceAddress.(*net.IPNet)
+		if ok == false || network.IP.IsGlobalUnicast() == false {
+			continue
+		}
+
+		if family == AddressFamilyAny || addressFamilyOfIP(network.IP) == family {
+			return network.IP.String()
+		}
+	}
+
+	return
+}
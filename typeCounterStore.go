@@ -0,0 +1,437 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sort"
+	"sync"
+)
+
+// counterType
+//
+// English:
+//
+//  Type of a replicated counter, counters of different types don't share values even with the
+//  same name
+//
+// Português:
+//
+//  Tipo de um contador replicado, contadores de tipos diferentes não compartilham valores mesmo com
+//  o mesmo nome
+type counterType string
+
+const (
+	kCounterTypeGrow             counterType = "g"
+	kCounterTypePositiveNegative counterType = "pn"
+)
+
+// counterKey
+//
+// English:
+//
+//  Identifies a counter in the store
+//
+// Português:
+//
+//  Identifica um contador no armazenamento
+type counterKey struct {
+	Type counterType
+	Name string
+}
+
+// counterEntry
+//
+// English:
+//
+//  Slot of a counter written by a single node. The values only grow, so the merge of two entries
+//  of the same slot is the greatest value of each field
+//
+//   Fields:
+//     Type: type of the counter
+//     Name: name of the counter
+//     Slot: owner of the slot, the node ID
+//     Incarnation: start time of the process of the owner that wrote the values
+//     Increment: sum of the increments made by the owner
+//     Decrement: sum of the decrements made by the owner, only for kCounterTypePositiveNegative
+//
+// Português:
+//
+//  Posição de um contador escrita por um único node. Os valores só crescem, assim a mescla de duas
+//  entradas da mesma posição é o maior valor de cada campo
+//
+//   Campos:
+//     Type: tipo do contador
+//     Name: nome do contador
+//     Slot: dono da posição, o ID do node
+//     Incarnation: momento de início do processo do dono que escreveu os valores
+//     Increment: soma dos incrementos feitos pelo dono
+//     Decrement: soma dos decrementos feitos pelo dono, apenas para kCounterTypePositiveNegative
+type counterEntry struct {
+	Type        counterType `json:"y"`
+	Name        string      `json:"c"`
+	Slot        string      `json:"s"`
+	Incarnation int64       `json:"e,omitempty"`
+	Increment   uint64      `json:"p,omitempty"`
+	Decrement   uint64      `json:"n,omitempty"`
+}
+
+// dominates
+//
+// English:
+//
+//  Returns true if no field of other is greater than the same field of the entry
+//
+// Português:
+//
+//  Retorna true se nenhum campo de other é maior que o mesmo campo da entrada
+func (e counterEntry) dominates(other counterEntry) (dominates bool) {
+	return e.Increment >= other.Increment && e.Decrement >= other.Decrement
+}
+
+// counterStore
+//
+// English:
+//
+//  Local copy of the replicated counters.
+//
+//  The slot of this node is kept by the node ID, so a restart doesn't create a new slot. The values
+//  written by the previous processes of the node, received from the other members, are the base of
+//  the slot and the changes of this process are added to the base, so they are never hidden by the
+//  older and greater values
+//
+//   Fields:
+//     slot: slot of this node, the node ID
+//     incarnation: start time of this process
+//     base: greatest values of the slot of this node written by the previous processes
+//     local: changes made by this process
+//
+// Português:
+//
+//  Cópia local dos contadores replicados.
+//
+//  A posição deste node é mantida pelo ID do node, assim um reinício não cria uma nova posição. Os
+//  valores escritos pelos processos anteriores do node, recebidos dos demais membros, são a base da
+//  posição e as alterações deste processo são somadas à base, assim elas nunca são escondidas pelos
+//  valores mais antigos e maiores
+//
+//   Campos:
+//     slot: posição deste node, o ID do node
+//     incarnation: momento de início deste processo
+//     base: maiores valores da posição deste node escritos pelos processos anteriores
+//     local: alterações feitas por este processo
+type counterStore struct {
+	mutex       sync.RWMutex
+	counters    map[counterKey]map[string]counterEntry
+	slot        string
+	incarnation int64
+	base        map[counterKey]counterEntry
+	local       map[counterKey]counterEntry
+}
+
+// newCounterStore
+//
+// English:
+//
+//  Returns a store without counters
+//
+//   Input:
+//     incarnation: start time of this process, greater than the one of the previous processes
+//
+// Português:
+//
+//  Retorna um armazenamento sem contadores
+//
+//   Entrada:
+//     incarnation: momento de início deste processo, maior que o dos processos anteriores
+func newCounterStore(incarnation int64) (store *counterStore) {
+	return &counterStore{
+		counters:    make(map[counterKey]map[string]counterEntry),
+		incarnation: incarnation,
+		base:        make(map[counterKey]counterEntry),
+		local:       make(map[counterKey]counterEntry),
+	}
+}
+
+// setSlot
+//
+// English:
+//
+//  Defines the slot of this node and moves the changes made before to it
+//
+//   Input:
+//     slot: slot of this node, the node ID
+//
+// Português:
+//
+//  Define a posição deste node e move para ela as alterações feitas antes
+//
+//   Entrada:
+//     slot: posição deste node, o ID do node
+func (e *counterStore) setSlot(slot string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for key := range e.local {
+		delete(e.slots(key), e.slot)
+	}
+
+	e.slot = slot
+	e.base = make(map[counterKey]counterEntry)
+
+	for key := range e.local {
+		e.slots(key)[slot] = e.own(key)
+	}
+}
+
+// isLocal
+//
+// English:
+//
+//  Returns true if the entry is of the slot of this node
+//
+// Português:
+//
+//  Retorna true se a entrada é da posição deste node
+func (e *counterStore) isLocal(entry counterEntry) (local bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return entry.Slot == e.slot
+}
+
+// add
+//
+// English:
+//
+//  Adds a local change to the slot of this node
+//
+//   Input:
+//     key: counter
+//     increment: value added to the increments
+//     decrement: value added to the decrements
+//
+//   Output:
+//     entry: the new slot, to be sent to the other members
+//
+// Português:
+//
+//  Adiciona uma alteração local à posição deste node
+//
+//   Entrada:
+//     key: contador
+//     increment: valor somado aos incrementos
+//     decrement: valor somado aos decrementos
+//
+//   Saída:
+//     entry: a nova posição, para ser enviada aos demais membros
+func (e *counterStore) add(key counterKey, increment, decrement uint64) (entry counterEntry) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var local = e.local[key]
+	local.Increment += increment
+	local.Decrement += decrement
+	e.local[key] = local
+
+	entry = e.own(key)
+	e.slots(key)[e.slot] = entry
+	return
+}
+
+// own
+//
+// English:
+//
+//  Returns the slot of this node, the base plus the changes of this process. Must be called with
+//  the mutex locked
+//
+// Português:
+//
+//  Retorna a posição deste node, a base mais as alterações deste processo. Deve ser chamado com o
+//  mutex travado
+func (e *counterStore) own(key counterKey) (entry counterEntry) {
+	var base = e.base[key]
+	var local = e.local[key]
+
+	return counterEntry{
+		Type:        key.Type,
+		Name:        key.Name,
+		Slot:        e.slot,
+		Incarnation: e.incarnation,
+		Increment:   base.Increment + local.Increment,
+		Decrement:   base.Decrement + local.Decrement,
+	}
+}
+
+// merge
+//
+// English:
+//
+//  Merges a slot received from another member.
+//
+//  A value of the slot of this node written by a previous process raises the base of the slot. A
+//  value with the incarnation of this process is either an echo, ignored, or was mixed with a
+//  previous process by another member, and then the base is raised by the amount the value exceeds
+//  the slot. In the other slots, a value mixed from two processes of the owner takes the newest
+//  incarnation, so the owner never takes its own changes as base
+//
+//   Output:
+//     merged: the slot after the merge, to be sent to the other members
+//     changed: true if the slot had a greater value and changed the local copy
+//
+// Português:
+//
+//  Mescla uma posição recebida de outro membro.
+//
+//  Um valor da posição deste node escrito por um processo anterior aumenta a base da posição. Um
+//  valor com a encarnação deste processo é um eco, ignorado, ou foi misturado com um processo
+//  anterior por outro membro, e então a base é aumentada no quanto o valor excede a posição. Nas
+//  demais posições, um valor misturado de dois processos do dono recebe a encarnação mais nova,
+//  assim o dono nunca toma as suas próprias alterações como base
+//
+//   Saída:
+//     merged: a posição depois da mescla, para ser enviada aos demais membros
+//     changed: true se a posição tinha um valor maior e alterou a cópia local
+func (e *counterStore) merge(entry counterEntry) (merged counterEntry, changed bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var key = counterKey{Type: entry.Type, Name: entry.Name}
+	var slots = e.slots(key)
+	var current = slots[entry.Slot]
+
+	if entry.Slot == e.slot {
+		var base = e.base[key]
+		if entry.Incarnation != e.incarnation {
+			if base.dominates(entry) == true {
+				return
+			}
+
+			base.Increment, base.Decrement = maxUint64(base.Increment, entry.Increment), maxUint64(base.Decrement, entry.Decrement)
+		} else {
+			// English: a value of this process mixed with a previous process by another member
+			// Português: um valor deste processo misturado com um processo anterior por outro membro
+			var own = e.own(key)
+			if own.dominates(entry) == true {
+				return
+			}
+
+			base.Increment += maxUint64(own.Increment, entry.Increment) - own.Increment
+			base.Decrement += maxUint64(own.Decrement, entry.Decrement) - own.Decrement
+		}
+		e.base[key] = base
+
+		merged = e.own(key)
+		slots[e.slot] = merged
+		changed = true
+		return
+	}
+
+	if current.dominates(entry) == true {
+		return
+	}
+
+	merged = entry
+	if entry.dominates(current) == false {
+		merged.Increment = maxUint64(entry.Increment, current.Increment)
+		merged.Decrement = maxUint64(entry.Decrement, current.Decrement)
+		if current.Incarnation > merged.Incarnation {
+			merged.Incarnation = current.Incarnation
+		}
+	}
+
+	slots[entry.Slot] = merged
+	changed = true
+	return
+}
+
+// value
+//
+// English:
+//
+//  Returns the sums of the increments and of the decrements of all slots of the counter
+//
+// Português:
+//
+//  Retorna as somas dos incrementos e dos decrementos de todas as posições do contador
+func (e *counterStore) value(key counterKey) (increment, decrement uint64) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for _, entry := range e.counters[key] {
+		increment += entry.Increment
+		decrement += entry.Decrement
+	}
+
+	return
+}
+
+// snapshot
+//
+// English:
+//
+//  Returns all slots of all counters, ordered by type, name and slot
+//
+// Português:
+//
+//  Retorna todas as posições de todos os contadores, ordenadas por tipo, nome e posição
+func (e *counterStore) snapshot() (entries []counterEntry) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	entries = make([]counterEntry, 0)
+	for _, slots := range e.counters {
+		for _, entry := range slots {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+
+		return entries[i].Slot < entries[j].Slot
+	})
+	return
+}
+
+// maxUint64
+//
+// English:
+//
+//  Returns the greatest of the two values
+//
+// Português:
+//
+//  Retorna o maior dos dois valores
+func maxUint64(a, b uint64) (greatest uint64) {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// slots
+//
+// English:
+//
+//  Returns the slots of the counter, creating them on first use. Must be called with the mutex
+//  locked
+//
+// Português:
+//
+//  Retorna as posições do contador, criando-as no primeiro uso. Deve ser chamado com o mutex
+//  travado
+func (e *counterStore) slots(key counterKey) (slots map[string]counterEntry) {
+	slots = e.counters[key]
+	if slots == nil {
+		slots = make(map[string]counterEntry)
+		e.counters[key] = slots
+	}
+
+	return
+}
@@ -0,0 +1,134 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"testing"
+)
+
+func TestCounterStoreMerge(t *testing.T) {
+	var key = counterKey{Type: kCounterTypePositiveNegative, Name: "c"}
+	var entry = func(slot string, incarnation int64, increment, decrement uint64) counterEntry {
+		return counterEntry{Type: key.Type, Name: key.Name, Slot: slot, Incarnation: incarnation, Increment: increment, Decrement: decrement}
+	}
+
+	// English: this node, "a", counted 2 in each test
+	// Português: este node, "a", contou 2 em cada teste
+	var tests = []struct {
+		name          string
+		current       []counterEntry
+		entry         counterEntry
+		changed       bool
+		wantIncrement uint64
+		wantDecrement uint64
+	}{
+		{name: "new slot", entry: entry("b", 1, 3, 1), changed: true, wantIncrement: 5, wantDecrement: 1},
+		{name: "greater values", current: []counterEntry{entry("b", 1, 3, 1)}, entry: entry("b", 1, 5, 2), changed: true, wantIncrement: 7, wantDecrement: 2},
+		{name: "smaller values", current: []counterEntry{entry("b", 1, 5, 2)}, entry: entry("b", 1, 3, 1), changed: false, wantIncrement: 7, wantDecrement: 2},
+		{name: "mixed values", current: []counterEntry{entry("b", 1, 5, 1)}, entry: entry("b", 2, 3, 4), changed: true, wantIncrement: 7, wantDecrement: 4},
+		{name: "other slots are added", current: []counterEntry{entry("c", 1, 10, 0)}, entry: entry("b", 1, 3, 1), changed: true, wantIncrement: 15, wantDecrement: 1},
+		{name: "echo of the own slot is ignored", entry: entry("a", 100, 2, 0), changed: false, wantIncrement: 2, wantDecrement: 0},
+		{name: "own slot mixed with a previous process raises the base", entry: entry("a", 100, 50, 3), changed: true, wantIncrement: 50, wantDecrement: 3},
+		{name: "own slot of a previous process is the base", entry: entry("a", 1, 50, 7), changed: true, wantIncrement: 52, wantDecrement: 7},
+		{name: "smaller base is ignored", current: []counterEntry{entry("a", 1, 50, 7)}, entry: entry("a", 1, 40, 7), changed: false, wantIncrement: 52, wantDecrement: 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store = newCounterStore(100)
+			store.setSlot("a")
+			store.add(key, 2, 0)
+
+			for _, current := range test.current {
+				store.merge(current)
+			}
+
+			if _, changed := store.merge(test.entry); changed != test.changed {
+				t.Fatalf("merge() changed = %v, want %v", changed, test.changed)
+			}
+
+			var increment, decrement = store.value(key)
+			if increment != test.wantIncrement || decrement != test.wantDecrement {
+				t.Fatalf("value() = %d, %d, want %d, %d", increment, decrement, test.wantIncrement, test.wantDecrement)
+			}
+		})
+	}
+}
+
+func TestCounterStoreMixedIncarnation(t *testing.T) {
+	var key = counterKey{Type: kCounterTypePositiveNegative, Name: "c"}
+
+	var store = newCounterStore(100)
+	store.setSlot("a")
+	store.merge(counterEntry{Type: key.Type, Name: key.Name, Slot: "b", Incarnation: 2, Increment: 1, Decrement: 9})
+
+	var merged, _ = store.merge(counterEntry{Type: key.Type, Name: key.Name, Slot: "b", Incarnation: 1, Increment: 5})
+	if merged.Incarnation != 2 {
+		t.Fatalf("mixed slot has incarnation %d, want the newest, 2", merged.Incarnation)
+	}
+}
+
+func TestCounterStoreRestart(t *testing.T) {
+	// English: the previous process of node "a" counted before, the new process counts early, before
+	// receiving the slot back from the member "b", and again after
+	// Português: o processo anterior do node "a" contou antes, o novo processo conta cedo, antes de
+	// receber a posição de volta do membro "b", e de novo depois
+	var tests = []struct {
+		name          string
+		counter       counterType
+		previous      [2]uint64
+		early         [2]uint64
+		after         [2]uint64
+		wantIncrement uint64
+		wantDecrement uint64
+	}{
+		{name: "grow", counter: kCounterTypeGrow, previous: [2]uint64{10, 0}, early: [2]uint64{4, 0}, after: [2]uint64{1, 0}, wantIncrement: 15},
+		{name: "positive negative", counter: kCounterTypePositiveNegative, previous: [2]uint64{100, 0}, early: [2]uint64{0, 3}, after: [2]uint64{1, 0}, wantIncrement: 101, wantDecrement: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var key = counterKey{Type: test.counter, Name: "c"}
+
+			var previous = newCounterStore(1)
+			previous.setSlot("a")
+
+			var member = newCounterStore(1)
+			member.setSlot("b")
+			member.merge(previous.add(key, test.previous[0], test.previous[1]))
+
+			var restarted = newCounterStore(2)
+			restarted.setSlot("a")
+			member.merge(restarted.add(key, test.early[0], test.early[1]))
+
+			// English: push/pull in both directions, twice, so the mixed slot returns to the restarted node
+			// Português: push/pull nas duas direções, duas vezes, assim a posição misturada volta ao node reiniciado
+			for i := 0; i != 2; i += 1 {
+				for _, entry := range member.snapshot() {
+					restarted.merge(entry)
+				}
+				for _, entry := range restarted.snapshot() {
+					member.merge(entry)
+				}
+			}
+
+			member.merge(restarted.add(key, test.after[0], test.after[1]))
+
+			for _, store := range []*counterStore{restarted, member} {
+				var increment, decrement = store.value(key)
+				if increment != test.wantIncrement || decrement != test.wantDecrement {
+					t.Fatalf("value() = %d, %d, want %d, %d", increment, decrement, test.wantIncrement, test.wantDecrement)
+				}
+			}
+
+			var slots = 0
+			for _, entry := range member.snapshot() {
+				if entry.Slot == "a" {
+					slots += 1
+				}
+			}
+
+			if slots != 1 {
+				t.Fatalf("member has %d slots of the restarted node, want 1", slots)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package iotmaker_docker_builder_demo
+
+// Discovery
+//
+// English:
+//
+//  Interface of the providers used to find the addresses of the other instances
+//
+//   Discover() output:
+//     addresses: list of addresses in the IP, host, IP:port or host:port format. When the port is
+//                omitted, the synchronism port defined by Init() is used
+//     err: standard error object
+//
+// Português:
+//
+//  Interface dos provedores usados para encontrar os endereços das demais instâncias
+//
+//   Saída de Discover():
+//     addresses: lista de endereços no formato IP, host, IP:porta ou host:porta. Quando a porta é
+//                omitida, a porta de sincronismo definida por Init() é usada
+//     err: objeto de erro padrão
+type Discovery interface {
+	Discover() (addresses []string, err error)
+}
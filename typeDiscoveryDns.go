@@ -0,0 +1,104 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"github.com/armon/go-metrics"
+	"net"
+	"time"
+)
+
+// DiscoveryDns
+//
+// English:
+//
+//  Finds the instances by the A and AAAA records of the names of the services/containers
+//
+//   Fields:
+//     ServiceNames: names of the services/containers
+//     Resolver: resolver of the names. nil uses the resolver of the Server, see Server.SetResolver()
+//     Family: preferred family. When a name has addresses of both families, only the addresses of
+//             this family are used
+//
+// Português:
+//
+//  Encontra as instâncias pelos registros A e AAAA dos nomes dos serviços/containers
+//
+//   Campos:
+//     ServiceNames: nomes dos serviços/containers
+//     Resolver: resolvedor dos nomes. nil usa o resolvedor do Server, veja Server.SetResolver()
+//     Family: família preferida. Quando um nome tem endereços das duas famílias, apenas os endereços
+//             desta família são usados
+type DiscoveryDns struct {
+	ServiceNames []string
+	Resolver     Resolver
+	Family       AddressFamily
+
+	// English: go-metrics instance of the Server, defined by Server.bindDiscovery(). nil uses the global
+	// instance
+	//
+	// Português: instância do go-metrics do Server, definida por Server.bindDiscovery(). nil usa a
+	// instância global
+	metrics *metrics.Metrics
+}
+
+// Discover
+//
+// English:
+//
+//  Returns the IP addresses of all names that could be resolved
+//
+//   Output:
+//     addresses: list of IP addresses
+//     err: error of the last name that could not be resolved, only when no name was resolved
+//
+// Português:
+//
+//  Retorna os endereços IP de todos os nomes que puderam ser resolvidos
+//
+//   Saída:
+//     addresses: lista de endereços IP
+//     err: erro do último nome que não pôde ser resolvido, apenas quando nenhum nome foi resolvido
+func (e *DiscoveryDns) Discover() (addresses []string, err error) {
+	var pass = false
+	var ipList []net.IP
+	var lookupErr error
+	var start time.Time
+	var labels []metrics.Label
+
+	var measureSince = metrics.MeasureSinceWithLabels
+	var incrCounter = metrics.IncrCounterWithLabels
+	if e.metrics != nil {
+		measureSince = e.metrics.MeasureSinceWithLabels
+		incrCounter = e.metrics.IncrCounterWithLabels
+	}
+
+	var resolver = e.Resolver
+	if resolver == nil {
+		resolver = NewDnsResolver("", 0, 0)
+	}
+
+	addresses = make([]string, 0)
+	for _, serviceName := range e.ServiceNames {
+		labels = []metrics.Label{{Name: "service", Value: serviceName}}
+
+		start = time.Now()
+		ipList, lookupErr = resolver.LookupIP(context.Background(), serviceName)
+		measureSince([]string{"discovery", "dns", "lookup"}, start, labels)
+		if lookupErr != nil {
+			incrCounter([]string{"discovery", "dns", "failure"}, 1, labels)
+			err = lookupErr
+			continue
+		}
+
+		pass = true
+		for _, nodeIP := range preferAddressFamily(ipList, e.Family) {
+			addresses = append(addresses, nodeIP.String())
+		}
+	}
+
+	if pass == true {
+		err = nil
+	}
+
+	return
+}
@@ -0,0 +1,98 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DiscoveryDnsSrv
+//
+// English:
+//
+//  Finds the instances by the SRV records, including the port of each instance
+//
+//   Fields:
+//     Service: name of the service, for example, "memberlist". Empty to look up Name directly
+//     Proto: protocol, for example, "tcp". Empty to look up Name directly
+//     Name: domain name, for example, "demo.default.svc.cluster.local"
+//     Resolver: resolver of the records and of their targets. nil uses the resolver of the Server,
+//               see Server.SetResolver()
+//     Family: preferred family of the targets. AddressFamilyAny uses the family of the Server, see
+//             Server.SetPreferredAddressFamily()
+//
+// Português:
+//
+//  Encontra as instâncias pelos registros SRV, incluindo a porta de cada instância
+//
+//   Campos:
+//     Service: nome do serviço, por exemplo, "memberlist". Vazio para consultar Name diretamente
+//     Proto: protocolo, por exemplo, "tcp". Vazio para consultar Name diretamente
+//     Name: nome de domínio, por exemplo, "demo.default.svc.cluster.local"
+//     Resolver: resolvedor dos registros e dos seus alvos. nil usa o resolvedor do Server, veja
+//               Server.SetResolver()
+//     Family: família preferida dos alvos. AddressFamilyAny usa a família do Server, veja
+//             Server.SetPreferredAddressFamily()
+type DiscoveryDnsSrv struct {
+	Service  string
+	Proto    string
+	Name     string
+	Resolver Resolver
+	Family   AddressFamily
+}
+
+// Discover
+//
+// English:
+//
+//  Returns the ip:port addresses of the targets of the SRV records. A target that can't be resolved
+//  is returned as host:port, so the join reports the error
+//
+//   Output:
+//     addresses: list of addresses in the ip:port or host:port format
+//     err: standard error object
+//
+// Português:
+//
+//  Retorna os endereços ip:porta dos alvos dos registros SRV. Um alvo que não pode ser resolvido é
+//  retornado como host:porta, assim a entrada no cluster reporta o erro
+//
+//   Saída:
+//     addresses: lista de endereços no formato ip:porta ou host:porta
+//     err: objeto de erro padrão
+func (e *DiscoveryDnsSrv) Discover() (addresses []string, err error) {
+	var resolver = e.Resolver
+	if resolver == nil {
+		resolver = NewDnsResolver("", 0, 0)
+	}
+
+	var srvList []*net.SRV
+	srvList, err = resolver.LookupSRV(context.Background(), e.Service, e.Proto, e.Name)
+	if err != nil {
+		return
+	}
+
+	addresses = make([]string, 0, len(srvList))
+	for _, srv := range srvList {
+		var host = strings.TrimSuffix(srv.Target, ".")
+		var port = strconv.Itoa(int(srv.Port))
+
+		if net.ParseIP(host) != nil {
+			addresses = append(addresses, net.JoinHostPort(host, port))
+			continue
+		}
+
+		var ipList, lookupErr = resolver.LookupIP(context.Background(), host)
+		if lookupErr != nil || len(ipList) == 0 {
+			addresses = append(addresses, net.JoinHostPort(host, port))
+			continue
+		}
+
+		for _, ip := range preferAddressFamily(ipList, e.Family) {
+			addresses = append(addresses, net.JoinHostPort(ip.String(), port))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package iotmaker_docker_builder_demo_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	demo "github.com/helmutkemper/iotmaker.docker.builder.demo"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/clustertest"
+)
+
+func TestDiscoveryDnsSrv(t *testing.T) {
+	var tests = []struct {
+		name    string
+		srv     demo.DiscoveryDnsSrv
+		prepare func(resolver *clustertest.FakeResolver)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "IP targets",
+			srv:  demo.DiscoveryDnsSrv{Name: "cluster"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("cluster", net.SRV{Target: "10.0.0.1", Port: 7946}, net.SRV{Target: "fd00::1", Port: 7947})
+			},
+			want: "10.0.0.1:7946,[fd00::1]:7947",
+		},
+		{
+			name: "service and protocol",
+			srv:  demo.DiscoveryDnsSrv{Service: "gossip", Proto: "tcp", Name: "cluster"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("_gossip._tcp.cluster", net.SRV{Target: "10.0.0.1", Port: 7946})
+			},
+			want: "10.0.0.1:7946",
+		},
+		{
+			name: "host targets resolved",
+			srv:  demo.DiscoveryDnsSrv{Name: "cluster"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("cluster", net.SRV{Target: "node-0.cluster.", Port: 7946})
+				resolver.SetAnswer("node-0.cluster", "10.0.0.1", "10.0.0.2")
+			},
+			want: "10.0.0.1:7946,10.0.0.2:7946",
+		},
+		{
+			name: "preferred family",
+			srv:  demo.DiscoveryDnsSrv{Name: "cluster", Family: demo.AddressFamilyIPv4},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("cluster", net.SRV{Target: "node-0", Port: 7946})
+				resolver.SetAnswer("node-0", "fd00::1", "10.0.0.1")
+			},
+			want: "10.0.0.1:7946",
+		},
+		{
+			name: "unresolved target kept",
+			srv:  demo.DiscoveryDnsSrv{Name: "cluster"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("cluster", net.SRV{Target: "node-0", Port: 7946})
+			},
+			want: "node-0:7946",
+		},
+		{
+			name: "no records",
+			srv:  demo.DiscoveryDnsSrv{Name: "cluster"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetSRV("cluster")
+			},
+			want: "",
+		},
+		{
+			name:    "not found",
+			srv:     demo.DiscoveryDnsSrv{Name: "cluster"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var resolver = &clustertest.FakeResolver{}
+			if test.prepare != nil {
+				test.prepare(resolver)
+			}
+
+			var discovery = test.srv
+			discovery.Resolver = resolver
+
+			var addresses, err = discovery.Discover()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Discover() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if strings.Join(addresses, ",") != test.want {
+				t.Fatalf("Discover() = %v, want %s", addresses, test.want)
+			}
+		})
+	}
+}
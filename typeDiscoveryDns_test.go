@@ -0,0 +1,95 @@
+package iotmaker_docker_builder_demo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	demo "github.com/helmutkemper/iotmaker.docker.builder.demo"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/clustertest"
+)
+
+func TestDiscoveryDns(t *testing.T) {
+	var tests = []struct {
+		name     string
+		services []string
+		family   demo.AddressFamily
+		prepare  func(resolver *clustertest.FakeResolver)
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "one service",
+			services: []string{"app"},
+			prepare:  func(resolver *clustertest.FakeResolver) { resolver.SetAnswer("app", "10.0.0.1", "10.0.0.2") },
+			want:     "10.0.0.1,10.0.0.2",
+		},
+		{
+			name:     "several services",
+			services: []string{"app", "worker"},
+			prepare: func(resolver *clustertest.FakeResolver) {
+				resolver.SetAnswer("app", "10.0.0.1")
+				resolver.SetAnswer("worker", "10.0.0.2")
+			},
+			want: "10.0.0.1,10.0.0.2",
+		},
+		{
+			name:     "one service not found",
+			services: []string{"app", "missing"},
+			prepare:  func(resolver *clustertest.FakeResolver) { resolver.SetAnswer("app", "10.0.0.1") },
+			want:     "10.0.0.1",
+		},
+		{
+			name:     "all services not found",
+			services: []string{"missing"},
+			wantErr:  true,
+		},
+		{
+			name:     "timeout",
+			services: []string{"app"},
+			prepare:  func(resolver *clustertest.FakeResolver) { resolver.SetTimeout("app") },
+			wantErr:  true,
+		},
+		{
+			name:     "preferred IPv6",
+			services: []string{"app"},
+			family:   demo.AddressFamilyIPv6,
+			prepare:  func(resolver *clustertest.FakeResolver) { resolver.SetAnswer("app", "10.0.0.1", "fd00::1") },
+			want:     "fd00::1",
+		},
+		{
+			name:     "preferred family not found",
+			services: []string{"app"},
+			family:   demo.AddressFamilyIPv6,
+			prepare:  func(resolver *clustertest.FakeResolver) { resolver.SetAnswer("app", "10.0.0.1") },
+			want:     "10.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var resolver = &clustertest.FakeResolver{}
+			if test.prepare != nil {
+				test.prepare(resolver)
+			}
+
+			var discovery = &demo.DiscoveryDns{ServiceNames: test.services, Resolver: resolver, Family: test.family}
+			var addresses, err = discovery.Discover()
+			if test.wantErr == true {
+				var dnsErr interface{ Temporary() bool }
+				if err == nil || errors.As(err, &dnsErr) == false {
+					t.Fatalf("Discover() error = %v, want a DNS error", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+
+			if strings.Join(addresses, ",") != test.want {
+				t.Fatalf("Discover() = %v, want %s", addresses, test.want)
+			}
+		})
+	}
+}
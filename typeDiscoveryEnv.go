@@ -0,0 +1,56 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"os"
+	"strings"
+)
+
+// DiscoveryEnv
+//
+// English:
+//
+//  Reads the list of addresses from an environment variable
+//
+//   Fields:
+//     Name: name of the environment variable, for example, "DEMO_PEERS"
+//     Separator: separator of the addresses, by default, ","
+//
+// Português:
+//
+//  Lê a lista de endereços de uma variável de ambiente
+//
+//   Campos:
+//     Name: nome da variável de ambiente, por exemplo, "DEMO_PEERS"
+//     Separator: separador dos endereços, por padrão, ","
+type DiscoveryEnv struct {
+	Name      string
+	Separator string
+}
+
+// Discover
+//
+// English:
+//
+//  Returns the addresses of the environment variable, ignoring blank entries
+//
+// Português:
+//
+//  Retorna os endereços da variável de ambiente, ignorando as entradas vazias
+func (e *DiscoveryEnv) Discover() (addresses []string, err error) {
+	var separator = e.Separator
+	if separator == "" {
+		separator = ","
+	}
+
+	addresses = make([]string, 0)
+	for _, address := range strings.Split(os.Getenv(e.Name), separator) {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return
+}
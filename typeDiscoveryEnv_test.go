@@ -0,0 +1,50 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiscoveryEnv(t *testing.T) {
+	var tests = []struct {
+		name      string
+		value     string
+		separator string
+		want      string
+	}{
+		{name: "default separator", value: "10.0.0.1:7946,10.0.0.2", want: "10.0.0.1:7946,10.0.0.2"},
+		{name: "spaces and empty items", value: " 10.0.0.1 ,, 10.0.0.2 ,", want: "10.0.0.1,10.0.0.2"},
+		{name: "custom separator", value: "10.0.0.1;10.0.0.2", separator: ";", want: "10.0.0.1,10.0.0.2"},
+		{name: "empty", value: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("DISCOVERY_ENV_TEST", test.value)
+
+			var discovery = &DiscoveryEnv{Name: "DISCOVERY_ENV_TEST", Separator: test.separator}
+			var addresses, err = discovery.Discover()
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+
+			if strings.Join(addresses, ",") != test.want {
+				t.Fatalf("Discover() = %v, want %s", addresses, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryStatic(t *testing.T) {
+	var discovery = &DiscoveryStatic{Addresses: []string{"10.0.0.1", "10.0.0.2"}}
+
+	var addresses, err = discovery.Discover()
+	if err != nil || strings.Join(addresses, ",") != "10.0.0.1,10.0.0.2" {
+		t.Fatalf("Discover() = %v, %v", addresses, err)
+	}
+
+	addresses[0] = "changed by the caller"
+	if discovery.Addresses[0] != "10.0.0.1" {
+		t.Fatal("Discover() shares the slice with the provider")
+	}
+}
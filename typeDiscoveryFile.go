@@ -0,0 +1,110 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiscoveryFile
+//
+// English:
+//
+//  Reads the list of addresses from a file, one address per line. Blank lines and lines starting
+//  with # are ignored. The file is only read again when its size or modification time changes
+//
+//   Fields:
+//     Path: path of the file
+//
+// Português:
+//
+//  Lê a lista de endereços de um arquivo, um endereço por linha. Linhas vazias e linhas iniciadas por
+//  # são ignoradas. O arquivo só é lido novamente quando o tamanho ou a data de modificação muda
+//
+//   Campos:
+//     Path: caminho do arquivo
+type DiscoveryFile struct {
+	Path string
+
+	mutex     sync.Mutex
+	modTime   time.Time
+	size      int64
+	addresses []string
+}
+
+// Discover
+//
+// English:
+//
+//  Returns the addresses of the file, reading it again only if it has changed
+//
+//   Output:
+//     addresses: list of addresses
+//     err: standard error object
+//
+// Português:
+//
+//  Retorna os endereços do arquivo, lendo-o novamente apenas se ele mudou
+//
+//   Saída:
+//     addresses: lista de endereços
+//     err: objeto de erro padrão
+func (e *DiscoveryFile) Discover() (addresses []string, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var info os.FileInfo
+	info, err = os.Stat(e.Path)
+	if err != nil {
+		return
+	}
+
+	if e.addresses == nil || info.ModTime().Equal(e.modTime) == false || info.Size() != e.size {
+		e.addresses, err = e.read()
+		if err != nil {
+			e.addresses = nil
+			return
+		}
+
+		e.modTime = info.ModTime()
+		e.size = info.Size()
+	}
+
+	addresses = make([]string, len(e.addresses))
+	copy(addresses, e.addresses)
+	return
+}
+
+// read
+//
+// English:
+//
+//  Reads all addresses of the file
+//
+// Português:
+//
+//  Lê todos os endereços do arquivo
+func (e *DiscoveryFile) read() (addresses []string, err error) {
+	var file *os.File
+	file, err = os.Open(e.Path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	addresses = make([]string, 0)
+	var scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		addresses = append(addresses, line)
+	}
+
+	err = scanner.Err()
+	return
+}
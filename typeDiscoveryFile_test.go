@@ -0,0 +1,72 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscoveryFile(t *testing.T) {
+	var tests = []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "one address per line", content: "10.0.0.1:7946\n10.0.0.2\n", want: "10.0.0.1:7946,10.0.0.2"},
+		{name: "comments and blank lines", content: "# seeds\n\n  10.0.0.1  \n#10.0.0.2\n", want: "10.0.0.1"},
+		{name: "empty", content: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path = filepath.Join(t.TempDir(), "seeds")
+			if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var discovery = &DiscoveryFile{Path: path}
+			var addresses, err = discovery.Discover()
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+
+			if strings.Join(addresses, ",") != test.want {
+				t.Fatalf("Discover() = %v, want %s", addresses, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryFileChanges(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "seeds")
+	var discovery = &DiscoveryFile{Path: path}
+
+	if _, err := discovery.Discover(); os.IsNotExist(err) == false {
+		t.Fatalf("Discover() of a missing file error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("10.0.0.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var addresses, _ = discovery.Discover()
+	addresses[0] = "changed by the caller"
+
+	if addresses, _ = discovery.Discover(); strings.Join(addresses, ",") != "10.0.0.1" {
+		t.Fatalf("Discover() = %v, want the cached addresses", addresses)
+	}
+
+	if err := os.WriteFile(path, []byte("10.0.0.2\n10.0.0.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var later = time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if addresses, _ = discovery.Discover(); strings.Join(addresses, ",") != "10.0.0.2,10.0.0.3" {
+		t.Fatalf("Discover() = %v, want the new content", addresses)
+	}
+}
@@ -0,0 +1,35 @@
+package iotmaker_docker_builder_demo
+
+// DiscoveryStatic
+//
+// English:
+//
+//  Fixed list of seed addresses, useful on bare hosts
+//
+//   Fields:
+//     Addresses: list of addresses in the IP, host, IP:port or host:port format
+//
+// Português:
+//
+//  Lista fixa de endereços semente, útil em hosts sem orquestrador
+//
+//   Campos:
+//     Addresses: lista de endereços no formato IP, host, IP:porta ou host:porta
+type DiscoveryStatic struct {
+	Addresses []string
+}
+
+// Discover
+//
+// English:
+//
+//  Returns a copy of the fixed list of addresses
+//
+// Português:
+//
+//  Retorna uma cópia da lista fixa de endereços
+func (e *DiscoveryStatic) Discover() (addresses []string, err error) {
+	addresses = make([]string, len(e.Addresses))
+	copy(addresses, e.Addresses)
+	return
+}
@@ -0,0 +1,116 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/memberlist"
+)
+
+const (
+	//kGossipMessageMaxSize
+	//
+	// English:
+	//
+	// Maximum size of a message sent by gossip. Larger messages don't fit the UDP packet of memberlist
+	// and are only synchronized by push/pull.
+	//
+	// Português:
+	//
+	// Tamanho máximo de uma mensagem enviada por fofoca. Mensagens maiores não cabem no pacote UDP da
+	// memberlist e só são sincronizadas por push/pull.
+	kGossipMessageMaxSize = 1024
+)
+
+// gossipMessageType
+//
+// English:
+//
+//  First byte of the user messages exchanged by memberlist, used to route the message
+//
+// Português:
+//
+//  Primeiro byte das mensagens de usuário trocadas pela memberlist, usado para rotear a mensagem
+type gossipMessageType byte
+
+const (
+	kGossipMessageKeyValue gossipMessageType = iota + 1
+	kGossipMessageKeyringRequest
+	kGossipMessageKeyringResponse
+	kGossipMessageCounter
+	kGossipMessageUserEvent
+)
+
+// encodeGossipMessage
+//
+// English:
+//
+//  Encodes the message type followed by the JSON of the message
+//
+// Português:
+//
+//  Codifica o tipo da mensagem seguido pelo JSON da mensagem
+func encodeGossipMessage(messageType gossipMessageType, message interface{}) (data []byte, err error) {
+	var body []byte
+	body, err = json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	data = make([]byte, 0, len(body)+1)
+	data = append(data, byte(messageType))
+	data = append(data, body...)
+	return
+}
+
+// gossipBroadcast
+//
+// English:
+//
+//  Implements memberlist.Broadcast. Broadcasts with the same non-empty name replace each other in
+//  the queue, so only the most recent value of a key is transmitted
+//
+// Português:
+//
+//  Implementa memberlist.Broadcast. Transmissões com o mesmo nome não vazio se substituem na fila,
+//  assim apenas o valor mais recente de uma chave é transmitido
+type gossipBroadcast struct {
+	name    string
+	message []byte
+}
+
+// Invalidates
+//
+// English:
+//
+//  Returns true if the broadcast replaces the other broadcast in the queue
+//
+// Português:
+//
+//  Retorna true se a transmissão substitui a outra transmissão na fila
+func (e *gossipBroadcast) Invalidates(other memberlist.Broadcast) bool {
+	var broadcast, ok = other.(*gossipBroadcast)
+	return ok == true && e.name != "" && e.name == broadcast.name
+}
+
+// Message
+//
+// English:
+//
+//  Returns the encoded message
+//
+// Português:
+//
+//  Retorna a mensagem codificada
+func (e *gossipBroadcast) Message() []byte {
+	return e.message
+}
+
+// Finished
+//
+// English:
+//
+//  Called by memberlist when the broadcast is no longer transmitted
+//
+// Português:
+//
+//  Chamado pela memberlist quando a transmissão não é mais transmitida
+func (e *gossipBroadcast) Finished() {}
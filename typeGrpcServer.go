@@ -0,0 +1,187 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer
+//
+// English:
+//
+//  Implements the SyncInstances service of typeGrpc.proto
+//
+// Português:
+//
+//  Implementa o serviço SyncInstances de typeGrpc.proto
+type grpcServer struct {
+	grpcProto.UnimplementedSyncInstancesServer
+	server *Server
+}
+
+// GrpcFuncInstanceIsReady
+//
+// English:
+//
+//  Answers if this instance is ready to receive requests, if it is alive and which checks failed
+//
+// Português:
+//
+//  Responde se esta instância está pronta para receber requisições, se está viva e quais verificações
+//  falharam
+func (e *grpcServer) GrpcFuncInstanceIsReady(ctx context.Context, in *grpcProto.Empty) (replay *grpcProto.InstanceIsReadyReplay, err error) {
+	var ready, live, failedChecks = e.server.getHealth()
+	replay = &grpcProto.InstanceIsReadyReplay{
+		IsReady:      ready,
+		IsLive:       live,
+		FailedChecks: failedChecks,
+	}
+	return
+}
+
+// GrpcFuncCommunication
+//
+// English:
+//
+//  Delivers a message sent by another instance to the function defined by SetCommunicationHandler()
+//  or, when the message has a method, to the function registered by Server.Handle()
+//
+// Português:
+//
+//  Entrega uma mensagem enviada por outra instância para a função definida por
+//  SetCommunicationHandler() ou, quando a mensagem tem um método, para a função registrada por
+//  Server.Handle()
+func (e *grpcServer) GrpcFuncCommunication(ctx context.Context, in *grpcProto.CommunicationRequest) (replay *grpcProto.CommunicationReplay, err error) {
+	var handler CommunicationHandler
+	if in.GetMethod() == "" {
+		handler = e.server.getCommunicationHandler()
+		if handler == nil {
+			err = status.Error(codes.Unimplemented, ErrCommunicationHandlerNotDefined.Error())
+			return
+		}
+	} else {
+		handler = e.server.getCallHandler(in.GetMethod())
+		if handler == nil {
+			err = status.Error(codes.Unimplemented, ErrMethodNotFound.Error())
+			return
+		}
+	}
+
+	var payload []byte
+	payload, err = handler(ctx, in.GetFrom(), in.GetPayload())
+	if err != nil {
+		err = status.Error(codes.Unknown, err.Error())
+		return
+	}
+
+	replay = &grpcProto.CommunicationReplay{
+		Payload: payload,
+	}
+	return
+}
+
+// GrpcFuncQuery
+//
+// English:
+//
+//  Acknowledges a query sent by Server.Query() of another instance and answers it with the function
+//  registered by Server.HandleQuery()
+//
+// Português:
+//
+//  Confirma uma consulta enviada por Server.Query() de outra instância e a responde com a função
+//  registrada por Server.HandleQuery()
+func (e *grpcServer) GrpcFuncQuery(in *grpcProto.QueryRequest, stream grpcProto.SyncInstances_GrpcFuncQueryServer) (err error) {
+	var handler = e.server.getQueryHandler(in.GetName())
+	if handler == nil {
+		err = status.Error(codes.Unimplemented, ErrMethodNotFound.Error())
+		return
+	}
+
+	err = stream.Send(&grpcProto.QueryReplay{Ack: true})
+	if err != nil {
+		return
+	}
+
+	var payload []byte
+	payload, err = handler(stream.Context(), in.GetFrom(), in.GetPayload())
+	if err != nil {
+		err = status.Error(codes.Unknown, err.Error())
+		return
+	}
+
+	return stream.Send(&grpcProto.QueryReplay{Payload: payload})
+}
+
+// GrpcFuncPubSub
+//
+// English:
+//
+//  Stream opened by a subscriber. The first request subscribes the node to its topics, the next ones
+//  change the topics or acknowledge the messages, while the pending messages of the subscriber are
+//  sent in order
+//
+// Português:
+//
+//  Stream aberto por um inscrito. A primeira requisição inscreve o node nos seus tópicos, as
+//  seguintes alteram os tópicos ou confirmam as mensagens, enquanto as mensagens pendentes do
+//  inscrito são enviadas em ordem
+func (e *grpcServer) GrpcFuncPubSub(stream grpcProto.SyncInstances_GrpcFuncPubSubServer) (err error) {
+	var request *grpcProto.PubSubRequest
+	request, err = stream.Recv()
+	if err != nil {
+		return
+	}
+
+	if request.GetSubscribe() == false || request.GetNode() == "" {
+		err = status.Error(codes.InvalidArgument, ErrPubSubNotSubscribed.Error())
+		return
+	}
+
+	var state = e.server.getPubSub()
+	var node = request.GetNode()
+	var session = state.open(node, request.GetTopics())
+	if session == nil {
+		err = status.Error(codes.Unavailable, "server stopped")
+		return
+	}
+	defer state.close(node, session)
+
+	var received = make(chan error, 1)
+	go func() {
+		for {
+			var request, err = stream.Recv()
+			if err != nil {
+				received <- err
+				return
+			}
+
+			if request.GetSubscribe() == true {
+				state.subscribe(node, request.GetTopics())
+			} else {
+				state.ack(node, request.GetAck())
+			}
+		}
+	}()
+
+	var sent uint64
+	for {
+		select {
+		case <-session.wake:
+		case <-session.done:
+			return
+		case <-received:
+			return
+		}
+
+		for _, message := range state.next(node, sent) {
+			err = stream.Send(message)
+			if err != nil {
+				return
+			}
+			sent = message.GetSequence()
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// KeyHash
+//
+// English:
+//
+//  Returns the position of the key in the consistent hash ring
+//
+//  FNV-1a spreads keys that differ only in the last characters, such as the virtual nodes "a#1" and
+//  "a#2", poorly, so the result goes through the splitmix64 finalizer
+//
+// Português:
+//
+//  Retorna a posição da chave no anel de hash consistente
+//
+//  O FNV-1a distribui mal chaves que diferem apenas nos últimos caracteres, como os nodes virtuais
+//  "a#1" e "a#2", por isto o resultado passa pelo finalizador do splitmix64
+func KeyHash(key string) (hash uint64) {
+	var function = fnv.New64a()
+	_, _ = function.Write([]byte(key))
+	hash = function.Sum64()
+
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+	return
+}
+
+// RingRange
+//
+// English:
+//
+//  Range of positions of the hash ring, from Start (exclusive) to End (inclusive), that moved from
+//  one node to another. When Start is greater than End, the range wraps around the end of the ring
+//
+//   Fields:
+//     Start: first position of the range, exclusive
+//     End: last position of the range, inclusive
+//     From: previous owner. Empty if the ring was empty
+//     To: new owner. Empty if the ring became empty
+//
+// Português:
+//
+//  Faixa de posições do anel de hash, de Start (exclusivo) até End (inclusivo), que mudou de um node
+//  para outro. Quando Start é maior que End, a faixa dá a volta no fim do anel
+//
+//   Campos:
+//     Start: primeira posição da faixa, exclusiva
+//     End: última posição da faixa, inclusiva
+//     From: dono anterior. Vazio se o anel estava vazio
+//     To: novo dono. Vazio se o anel ficou vazio
+type RingRange struct {
+	Start uint64
+	End   uint64
+	From  string
+	To    string
+}
+
+// Contains
+//
+// English:
+//
+//  Returns true if the key belongs to the range
+//
+// Português:
+//
+//  Retorna true se a chave pertence à faixa
+func (e RingRange) Contains(key string) bool {
+	var hash = KeyHash(key)
+	if e.Start < e.End {
+		return hash > e.Start && hash <= e.End
+	}
+
+	return hash > e.Start || hash <= e.End
+}
+
+// ringPoint
+//
+// English:
+//
+//  Virtual node of the hash ring
+//
+// Português:
+//
+//  Node virtual do anel de hash
+type ringPoint struct {
+	hash uint64
+	node string
+}
+
+// hashRing
+//
+// English:
+//
+//  Immutable consistent hash ring. Each change of the members creates a new ring
+//
+// Português:
+//
+//  Anel de hash consistente imutável. Cada mudança dos membros cria um novo anel
+type hashRing struct {
+	points []ringPoint
+	size   int
+}
+
+// newHashRing
+//
+// English:
+//
+//  Creates a ring with virtualNodes * weight points for each node
+//
+//   Input:
+//     nodes: key: node name / value: weight of the node. Weights less than one count as one
+//     virtualNodes: number of points of a node with weight one
+//
+// Português:
+//
+//  Cria um anel com virtualNodes * weight pontos para cada node
+//
+//   Entrada:
+//     nodes: chave: nome do node / valor: peso do node. Pesos menores que um contam como um
+//     virtualNodes: número de pontos de um node com peso um
+func newHashRing(nodes map[string]int, virtualNodes int) (ring *hashRing) {
+	ring = &hashRing{
+		points: make([]ringPoint, 0),
+	}
+
+	for node, weight := range nodes {
+		if weight < 1 {
+			weight = 1
+		}
+
+		for i := 0; i < virtualNodes*weight; i += 1 {
+			ring.points = append(ring.points, ringPoint{hash: KeyHash(node + "#" + strconv.Itoa(i)), node: node})
+		}
+
+		if virtualNodes > 0 {
+			ring.size += 1
+		}
+	}
+
+	sort.Slice(ring.points, func(i, j int) bool {
+		if ring.points[i].hash != ring.points[j].hash {
+			return ring.points[i].hash < ring.points[j].hash
+		}
+
+		return ring.points[i].node < ring.points[j].node
+	})
+	return
+}
+
+// ownerOfHash
+//
+// English:
+//
+//  Returns the node of the first point at or after the position, wrapping around the ring
+//
+// Português:
+//
+//  Retorna o node do primeiro ponto na posição ou depois dela, dando a volta no anel
+func (e *hashRing) ownerOfHash(hash uint64) (node string) {
+	var index = e.search(hash)
+	if index < 0 {
+		return
+	}
+
+	return e.points[index].node
+}
+
+// owners
+//
+// English:
+//
+//  Returns up to n distinct nodes, walking the ring clockwise from the position of the key. n is
+//  limited to the number of nodes of the ring and n less than one returns nil
+//
+// Português:
+//
+//  Retorna até n nodes distintos, percorrendo o anel no sentido horário a partir da posição da
+//  chave. n é limitado ao número de nodes do anel e n menor que um retorna nil
+func (e *hashRing) owners(key string, n int) (nodes []string) {
+	if n > e.size {
+		n = e.size
+	}
+
+	if n <= 0 {
+		return
+	}
+
+	nodes = make([]string, 0, n)
+	var index = e.search(KeyHash(key))
+	if index < 0 {
+		return
+	}
+
+	var found = make(map[string]bool)
+	for i := 0; i != len(e.points) && len(nodes) < n; i += 1 {
+		var point = e.points[(index+i)%len(e.points)]
+		if found[point.node] == true {
+			continue
+		}
+
+		found[point.node] = true
+		nodes = append(nodes, point.node)
+	}
+
+	return
+}
+
+// search
+//
+// English:
+//
+//  Returns the index of the first point at or after the position, or -1 if the ring is empty
+//
+// Português:
+//
+//  Retorna o índice do primeiro ponto na posição ou depois dela, ou -1 se o anel está vazio
+func (e *hashRing) search(hash uint64) (index int) {
+	if len(e.points) == 0 {
+		return -1
+	}
+
+	index = sort.Search(len(e.points), func(i int) bool {
+		return e.points[i].hash >= hash
+	})
+	if index == len(e.points) {
+		index = 0
+	}
+
+	return
+}
+
+// movedRanges
+//
+// English:
+//
+//  Compares two rings and returns the ranges whose owner changed. Consecutive ranges with the same
+//  previous and new owner are merged
+//
+// Português:
+//
+//  Compara dois anéis e retorna as faixas cujo dono mudou. Faixas consecutivas com os mesmos donos
+//  anterior e novo são unidas
+func movedRanges(previous, next *hashRing) (moved []RingRange) {
+	moved = make([]RingRange, 0)
+
+	var boundaries = make([]uint64, 0, len(previous.points)+len(next.points))
+	for _, point := range previous.points {
+		boundaries = append(boundaries, point.hash)
+	}
+	for _, point := range next.points {
+		boundaries = append(boundaries, point.hash)
+	}
+	if len(boundaries) == 0 {
+		return
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	var unique = boundaries[:1]
+	for _, boundary := range boundaries[1:] {
+		if boundary != unique[len(unique)-1] {
+			unique = append(unique, boundary)
+		}
+	}
+
+	for i, end := range unique {
+		var start = unique[(i+len(unique)-1)%len(unique)]
+		var from = previous.ownerOfHash(end)
+		var to = next.ownerOfHash(end)
+		if from == to {
+			continue
+		}
+
+		var last = len(moved) - 1
+		if last >= 0 && moved[last].End == start && moved[last].From == from && moved[last].To == to {
+			moved[last].End = end
+			continue
+		}
+
+		moved = append(moved, RingRange{Start: start, End: end, From: from, To: to})
+	}
+
+	return
+}
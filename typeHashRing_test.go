@@ -0,0 +1,154 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHashRingOwners(t *testing.T) {
+	var ring = newHashRing(map[string]int{"a": 1, "b": 1, "c": 1}, 16)
+
+	var tests = []struct {
+		name string
+		n    int
+		want int
+	}{
+		{name: "negative", n: -1, want: 0},
+		{name: "zero", n: 0, want: 0},
+		{name: "one", n: 1, want: 1},
+		{name: "all", n: 3, want: 3},
+		{name: "more than the ring", n: 10, want: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var owners = ring.owners("key", test.n)
+			if len(owners) != test.want {
+				t.Fatalf("owners(%d) = %v, want %d nodes", test.n, owners, test.want)
+			}
+
+			var found = make(map[string]bool)
+			for _, owner := range owners {
+				if found[owner] == true {
+					t.Fatalf("owners(%d) = %v, repeated node %q", test.n, owners, owner)
+				}
+				found[owner] = true
+			}
+
+			if test.want != 0 && owners[0] != ring.ownerOfHash(KeyHash("key")) {
+				t.Fatalf("first owner %q differs from ownerOfHash() %q", owners[0], ring.ownerOfHash(KeyHash("key")))
+			}
+		})
+	}
+}
+
+func TestHashRingEmpty(t *testing.T) {
+	var tests = []struct {
+		name         string
+		nodes        map[string]int
+		virtualNodes int
+	}{
+		{name: "no nodes", nodes: map[string]int{}, virtualNodes: 16},
+		{name: "no virtual nodes", nodes: map[string]int{"a": 1}, virtualNodes: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ring = newHashRing(test.nodes, test.virtualNodes)
+			if owners := ring.owners("key", 1); owners != nil {
+				t.Fatalf("owners() = %v, want nil", owners)
+			}
+
+			if owner := ring.ownerOfHash(KeyHash("key")); owner != "" {
+				t.Fatalf("ownerOfHash() = %q, want empty", owner)
+			}
+		})
+	}
+}
+
+func TestHashRingWeight(t *testing.T) {
+	var ring = newHashRing(map[string]int{"light": 1, "heavy": 4, "invalid": -2}, 8)
+
+	var points = make(map[string]int)
+	for _, point := range ring.points {
+		points[point.node] += 1
+	}
+
+	var tests = []struct {
+		node string
+		want int
+	}{
+		{node: "light", want: 8},
+		{node: "heavy", want: 32},
+		{node: "invalid", want: 8},
+	}
+
+	for _, test := range tests {
+		if points[test.node] != test.want {
+			t.Errorf("%s has %d points, want %d", test.node, points[test.node], test.want)
+		}
+	}
+}
+
+func TestRingWeightLimit(t *testing.T) {
+	var tests = []struct {
+		name   string
+		weight int
+		want   int
+	}{
+		{name: "below one", weight: -5, want: 1},
+		{name: "in range", weight: 7, want: 7},
+		{name: "advertised by a remote member", weight: math.MaxInt, want: kRingMaxWeight},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var server = &Server{}
+			server.SetRingVirtualNodes(2)
+			server.updateRingNode("remote", test.weight, true)
+
+			if points := len(server.ring.points); points != 2*test.want {
+				t.Fatalf("ring has %d points, want %d", points, 2*test.want)
+			}
+		})
+	}
+}
+
+func TestMovedRanges(t *testing.T) {
+	var previous = newHashRing(map[string]int{"a": 1, "b": 1}, 32)
+	var next = newHashRing(map[string]int{"a": 1, "b": 1, "c": 1}, 32)
+
+	var moved = movedRanges(previous, next)
+	if len(moved) == 0 {
+		t.Fatal("no range moved to the new node")
+	}
+
+	for _, ranges := range moved {
+		if ranges.To != "c" {
+			t.Fatalf("range %+v moved between the old nodes", ranges)
+		}
+	}
+
+	for i := 0; i != 1000; i += 1 {
+		var key = "key-" + strconv.Itoa(i)
+		var from = previous.ownerOfHash(KeyHash(key))
+		var to = next.ownerOfHash(KeyHash(key))
+
+		var contained = false
+		for _, ranges := range moved {
+			if ranges.Contains(key) == true {
+				contained = true
+				break
+			}
+		}
+
+		if contained != (from != to) {
+			t.Fatalf("key %q moved from %q to %q, contained in the moved ranges: %v", key, from, to, contained)
+		}
+	}
+
+	if moved = movedRanges(next, next); len(moved) != 0 {
+		t.Fatalf("unchanged ring moved %v", moved)
+	}
+}
@@ -0,0 +1,89 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	//kHealthCheckTimeout
+	//
+	// English:
+	//
+	// Time limit of a health check registered with a timeout less than or equal to zero.
+	//
+	// Português:
+	//
+	// Tempo limite de uma verificação de saúde registrada com um tempo limite menor ou igual a zero.
+	kHealthCheckTimeout = time.Second * 1
+)
+
+// HealthCheckFunc
+//
+// English:
+//
+//  Function used to check the health of the instance
+//
+//   Input:
+//     ctx: context canceled when the time limit of the check expires
+//
+//   Output:
+//     err: nil if the check passed
+//
+// Português:
+//
+//  Função usada para verificar a saúde da instância
+//
+//   Entrada:
+//     ctx: contexto cancelado quando o tempo limite da verificação expira
+//
+//   Saída:
+//     err: nil se a verificação passou
+type HealthCheckFunc func(ctx context.Context) (err error)
+
+// healthCheck
+//
+// English:
+//
+//  Health check registered by AddReadinessCheck() or AddLivenessCheck()
+//
+// Português:
+//
+//  Verificação de saúde registrada por AddReadinessCheck() ou AddLivenessCheck()
+type healthCheck struct {
+	name     string
+	timeout  time.Duration
+	function HealthCheckFunc
+}
+
+// run
+//
+// English:
+//
+//  Runs the check within its time limit. A check that doesn't return in time fails
+//
+// Português:
+//
+//  Executa a verificação dentro do seu tempo limite. Uma verificação que não retorna a tempo falha
+func (e healthCheck) run() (err error) {
+	var timeout = e.timeout
+	if timeout <= 0 {
+		timeout = kHealthCheckTimeout
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var result = make(chan error, 1)
+	go func() {
+		result <- e.function(ctx)
+	}()
+
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -0,0 +1,239 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// keyValueEntry
+//
+// English:
+//
+//  Versioned value of a key of the replicated map. Deleted keys are kept as tombstones, so the
+//  deletion wins over older values received later
+//
+//   Fields:
+//     Key: key of the map
+//     Value: value of the key
+//     Time: time of the change, in nanoseconds, used by the last-writer-wins rule
+//     Node: name of the node that made the change, used to break ties
+//     Deleted: true if the key was deleted
+//
+// Português:
+//
+//  Valor versionado de uma chave do mapa replicado. Chaves apagadas são mantidas como lápides, assim
+//  a remoção vence valores mais antigos recebidos depois
+//
+//   Campos:
+//     Key: chave do mapa
+//     Value: valor da chave
+//     Time: momento da alteração, em nanossegundos, usado pela regra do último escritor vence
+//     Node: nome do node que fez a alteração, usado para desempatar
+//     Deleted: true se a chave foi apagada
+type keyValueEntry struct {
+	Key     string `json:"k"`
+	Value   []byte `json:"v,omitempty"`
+	Time    int64  `json:"t"`
+	Node    string `json:"n"`
+	Deleted bool   `json:"d,omitempty"`
+}
+
+// newerThan
+//
+// English:
+//
+//  Returns true if the entry wins over the other entry by the last-writer-wins rule
+//
+// Português:
+//
+//  Retorna true se a entrada vence a outra entrada pela regra do último escritor vence
+func (e keyValueEntry) newerThan(other keyValueEntry) bool {
+	if e.Time != other.Time {
+		return e.Time > other.Time
+	}
+
+	return e.Node > other.Node
+}
+
+// keyValueStore
+//
+// English:
+//
+//  Local copy of the replicated map
+//
+// Português:
+//
+//  Cópia local do mapa replicado
+type keyValueStore struct {
+	mutex   sync.RWMutex
+	entries map[string]keyValueEntry
+}
+
+// newKeyValueStore
+//
+// English:
+//
+//  Returns an empty map
+//
+// Português:
+//
+//  Retorna um mapa vazio
+func newKeyValueStore() (store *keyValueStore) {
+	return &keyValueStore{
+		entries: make(map[string]keyValueEntry),
+	}
+}
+
+// write
+//
+// English:
+//
+//  Writes a local change. The time is always greater than the time of the current entry, so a
+//  local change wins even if the clock of another node is ahead
+//
+//   Input:
+//     key: key of the map
+//     value: new value
+//     deleted: true to delete the key
+//     node: name of this node
+//
+//   Output:
+//     entry: the new entry, to be sent to the other members
+//
+// Português:
+//
+//  Escreve uma alteração local. O tempo é sempre maior que o tempo da entrada atual, assim uma
+//  alteração local vence mesmo que o relógio de outro node esteja adiantado
+//
+//   Entrada:
+//     key: chave do mapa
+//     value: novo valor
+//     deleted: true para apagar a chave
+//     node: nome deste node
+//
+//   Saída:
+//     entry: a nova entrada, para ser enviada aos demais membros
+func (e *keyValueStore) write(key string, value []byte, deleted bool, node string) (entry keyValueEntry) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var now = time.Now().UnixNano()
+	if current, found := e.entries[key]; found == true && current.Time >= now {
+		now = current.Time + 1
+	}
+
+	var valueCopy []byte
+	if deleted == false {
+		valueCopy = make([]byte, len(value))
+		copy(valueCopy, value)
+	}
+
+	entry = keyValueEntry{
+		Key:     key,
+		Value:   valueCopy,
+		Time:    now,
+		Node:    node,
+		Deleted: deleted,
+	}
+	e.entries[key] = entry
+	return
+}
+
+// merge
+//
+// English:
+//
+//  Merges an entry received from another member
+//
+//   Output:
+//     changed: true if the entry is newer and replaced the local value
+//
+// Português:
+//
+//  Mescla uma entrada recebida de outro membro
+//
+//   Saída:
+//     changed: true se a entrada é mais nova e substituiu o valor local
+func (e *keyValueStore) merge(entry keyValueEntry) (changed bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if current, found := e.entries[entry.Key]; found == true && entry.newerThan(current) == false {
+		return
+	}
+
+	e.entries[entry.Key] = entry
+	changed = true
+	return
+}
+
+// get
+//
+// English:
+//
+//  Returns a copy of the value of the key
+//
+// Português:
+//
+//  Retorna uma cópia do valor da chave
+func (e *keyValueStore) get(key string) (value []byte, found bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	var entry keyValueEntry
+	entry, found = e.entries[key]
+	if found == false || entry.Deleted == true {
+		found = false
+		return
+	}
+
+	value = make([]byte, len(entry.Value))
+	copy(value, entry.Value)
+	return
+}
+
+// snapshot
+//
+// English:
+//
+//  Returns all entries, including the tombstones, ordered by key
+//
+// Português:
+//
+//  Retorna todas as entradas, incluindo as lápides, ordenadas pela chave
+func (e *keyValueStore) snapshot() (entries []keyValueEntry) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	entries = make([]keyValueEntry, 0, len(e.entries))
+	for _, entry := range e.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return
+}
+
+// prune
+//
+// English:
+//
+//  Removes the tombstones older than ttl
+//
+// Português:
+//
+//  Remove as lápides mais antigas que ttl
+func (e *keyValueStore) prune(ttl time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var limit = time.Now().Add(-ttl).UnixNano()
+	for key, entry := range e.entries {
+		if entry.Deleted == true && entry.Time < limit {
+			delete(e.entries, key)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyValueStoreMerge(t *testing.T) {
+	var current = keyValueEntry{Key: "k", Value: []byte("current"), Time: 10, Node: "b"}
+
+	var tests = []struct {
+		name      string
+		entry     keyValueEntry
+		changed   bool
+		wantValue string
+		wantFound bool
+	}{
+		{name: "newer time", entry: keyValueEntry{Key: "k", Value: []byte("new"), Time: 11, Node: "a"}, changed: true, wantValue: "new", wantFound: true},
+		{name: "older time", entry: keyValueEntry{Key: "k", Value: []byte("old"), Time: 9, Node: "c"}, changed: false, wantValue: "current", wantFound: true},
+		{name: "same time greater node", entry: keyValueEntry{Key: "k", Value: []byte("tie"), Time: 10, Node: "c"}, changed: true, wantValue: "tie", wantFound: true},
+		{name: "same time smaller node", entry: keyValueEntry{Key: "k", Value: []byte("tie"), Time: 10, Node: "a"}, changed: false, wantValue: "current", wantFound: true},
+		{name: "same entry", entry: current, changed: false, wantValue: "current", wantFound: true},
+		{name: "newer tombstone", entry: keyValueEntry{Key: "k", Time: 11, Node: "a", Deleted: true}, changed: true, wantFound: false},
+		{name: "older tombstone", entry: keyValueEntry{Key: "k", Time: 9, Node: "a", Deleted: true}, changed: false, wantValue: "current", wantFound: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store = newKeyValueStore()
+			store.merge(current)
+
+			if changed := store.merge(test.entry); changed != test.changed {
+				t.Fatalf("merge() = %v, want %v", changed, test.changed)
+			}
+
+			var value, found = store.get("k")
+			if found != test.wantFound || string(value) != test.wantValue {
+				t.Fatalf("get() = %q, %v, want %q, %v", value, found, test.wantValue, test.wantFound)
+			}
+		})
+	}
+}
+
+func TestKeyValueStoreConverges(t *testing.T) {
+	var entries = []keyValueEntry{
+		{Key: "k", Value: []byte("1"), Time: 1, Node: "a"},
+		{Key: "k", Value: []byte("2"), Time: 2, Node: "b"},
+		{Key: "k", Time: 3, Node: "a", Deleted: true},
+		{Key: "k", Value: []byte("4"), Time: 3, Node: "c"},
+	}
+
+	var orders = [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}, {1, 3, 0, 2}}
+	for _, order := range orders {
+		var store = newKeyValueStore()
+		for _, index := range order {
+			store.merge(entries[index])
+		}
+
+		var value, found = store.get("k")
+		if found == false || string(value) != "4" {
+			t.Fatalf("order %v: get() = %q, %v, want \"4\", true", order, value, found)
+		}
+	}
+}
+
+func TestKeyValueStoreWrite(t *testing.T) {
+	var store = newKeyValueStore()
+
+	var value = []byte("value")
+	var first = store.write("k", value, false, "a")
+	value[0] = 'X'
+
+	if got, _ := store.get("k"); string(got) != "value" {
+		t.Fatalf("write() kept the slice of the caller, get() = %q", got)
+	}
+
+	var second = store.write("k", nil, true, "a")
+	if second.newerThan(first) == false {
+		t.Fatalf("delete at %d is not newer than the write at %d", second.Time, first.Time)
+	}
+
+	if _, found := store.get("k"); found == true {
+		t.Fatal("get() found a deleted key")
+	}
+
+	if len(store.snapshot()) != 1 {
+		t.Fatal("snapshot() lost the tombstone")
+	}
+
+	store.prune(time.Hour)
+	if len(store.snapshot()) != 1 {
+		t.Fatal("prune() removed a recent tombstone")
+	}
+
+	store.merge(keyValueEntry{Key: "old", Time: time.Now().Add(-2 * time.Hour).UnixNano(), Node: "b", Deleted: true})
+	store.prune(time.Hour)
+
+	var snapshot = store.snapshot()
+	if len(snapshot) != 1 || snapshot[0].Key != "k" {
+		t.Fatalf("prune() left %v", snapshot)
+	}
+}
@@ -0,0 +1,75 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sync/atomic"
+)
+
+// lamportClock
+//
+// English:
+//
+//  Logical clock of Lamport. Each local event increments the clock and each received event moves
+//  the clock past its time, so an event always has a time greater than the events seen before it
+//
+// Português:
+//
+//  Relógio lógico de Lamport. Cada evento local incrementa o relógio e cada evento recebido move o
+//  relógio para depois do seu tempo, assim um evento sempre tem um tempo maior que os eventos vistos
+//  antes dele
+type lamportClock struct {
+	counter uint64
+}
+
+// time
+//
+// English:
+//
+//  Returns the current time of the clock
+//
+// Português:
+//
+//  Retorna o tempo atual do relógio
+func (e *lamportClock) time() (time uint64) {
+	return atomic.LoadUint64(&e.counter)
+}
+
+// increment
+//
+// English:
+//
+//  Advances the clock for a local event
+//
+//   Output:
+//     time: time of the local event
+//
+// Português:
+//
+//  Avança o relógio para um evento local
+//
+//   Saída:
+//     time: tempo do evento local
+func (e *lamportClock) increment() (time uint64) {
+	return atomic.AddUint64(&e.counter, 1)
+}
+
+// witness
+//
+// English:
+//
+//  Moves the clock past the time of a received event
+//
+// Português:
+//
+//  Move o relógio para depois do tempo de um evento recebido
+func (e *lamportClock) witness(time uint64) {
+	for {
+		var current = atomic.LoadUint64(&e.counter)
+		if time < current {
+			return
+		}
+
+		if atomic.CompareAndSwapUint64(&e.counter, current, time+1) == true {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLamportClockWitness(t *testing.T) {
+	var tests = []struct {
+		name    string
+		current uint64
+		witness uint64
+		want    uint64
+	}{
+		{name: "newer time", current: 5, witness: 9, want: 10},
+		{name: "same time", current: 5, witness: 5, want: 6},
+		{name: "older time", current: 5, witness: 3, want: 5},
+		{name: "zero", current: 0, witness: 0, want: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var clock = lamportClock{counter: test.current}
+			clock.witness(test.witness)
+
+			if clock.time() != test.want {
+				t.Fatalf("time() = %d, want %d", clock.time(), test.want)
+			}
+		})
+	}
+}
+
+func TestLamportClockConcurrent(t *testing.T) {
+	var clock lamportClock
+	var wait sync.WaitGroup
+
+	for i := 0; i != 8; i += 1 {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			for j := 0; j != 100; j += 1 {
+				clock.increment()
+				clock.witness(uint64(i * j))
+			}
+		}(i)
+	}
+	wait.Wait()
+
+	if clock.time() < 800 {
+		t.Fatalf("time() = %d, lost increments", clock.time())
+	}
+}
@@ -0,0 +1,352 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel
+//
+// English:
+//
+//  Severity of a log message
+//
+// Português:
+//
+//  Severidade de uma mensagem de log
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String
+//
+// English:
+//
+//  Returns the name of the level
+//
+// Português:
+//
+//  Retorna o nome do nível
+func (e LogLevel) String() string {
+	switch e {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// LogEncoder
+//
+// English:
+//
+//  Format of the lines written by the logger returned by NewLogger()
+//
+// Português:
+//
+//  Formato das linhas escritas pelo logger retornado por NewLogger()
+type LogEncoder int
+
+const (
+	// LogEncoderText
+	//
+	// English: time level message key=value ...
+	//
+	// Português: hora nível mensagem chave=valor ...
+	LogEncoderText LogEncoder = iota
+
+	// LogEncoderJSON
+	//
+	// English: {"time":"...","level":"...","msg":"...","key":value,...}
+	//
+	// Português: {"time":"...","level":"...","msg":"...","chave":valor,...}
+	LogEncoderJSON
+)
+
+// Logger
+//
+// English:
+//
+//  Structured logger used by Server.
+//
+//   Input:
+//     msg: constant message, in English
+//     fields: key/value pairs, the keys are strings, for example "node", name, "error", err
+//
+//   Note:
+//     * Use Server.SetLogger() to replace the default logger;
+//     * The implementations must be safe for concurrent use.
+//
+// Português:
+//
+//  Logger estruturado usado pelo Server.
+//
+//   Entrada:
+//     msg: mensagem constante, em inglês
+//     fields: pares chave/valor, as chaves são textos, por exemplo "node", name, "error", err
+//
+//   Nota:
+//     * Use Server.SetLogger() para trocar o logger padrão;
+//     * As implementações devem ser seguras para uso concorrente.
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+// defaultLogger
+//
+// English: used when Server.SetLogger() was not called
+//
+// Português: usado quando Server.SetLogger() não foi chamado
+var defaultLogger = NewLogger(os.Stderr, LogLevelWarn, LogEncoderText)
+
+// logger
+//
+// English:
+//
+//  Logger returned by NewLogger()
+//
+// Português:
+//
+//  Logger retornado por NewLogger()
+type logger struct {
+	mutex   sync.Mutex
+	writer  io.Writer
+	level   LogLevel
+	encoder LogEncoder
+}
+
+// NewLogger
+//
+// English:
+//
+//  Returns a Logger that writes one line per message
+//
+//   Input:
+//     writer: destination of the lines, for example os.Stderr
+//     level: messages below this level are discarded
+//     encoder: LogEncoderText or LogEncoderJSON
+//
+// Português:
+//
+//  Retorna um Logger que escreve uma linha por mensagem
+//
+//   Entrada:
+//     writer: destino das linhas, por exemplo os.Stderr
+//     level: mensagens abaixo deste nível são descartadas
+//     encoder: LogEncoderText ou LogEncoderJSON
+func NewLogger(writer io.Writer, level LogLevel, encoder LogEncoder) Logger {
+	return &logger{
+		writer:  writer,
+		level:   level,
+		encoder: encoder,
+	}
+}
+
+func (e *logger) Debug(msg string, fields ...interface{}) {
+	e.write(LogLevelDebug, msg, fields)
+}
+
+func (e *logger) Info(msg string, fields ...interface{}) {
+	e.write(LogLevelInfo, msg, fields)
+}
+
+func (e *logger) Warn(msg string, fields ...interface{}) {
+	e.write(LogLevelWarn, msg, fields)
+}
+
+func (e *logger) Error(msg string, fields ...interface{}) {
+	e.write(LogLevelError, msg, fields)
+}
+
+// write
+//
+// English:
+//
+//  Encodes and writes the message when the level is enabled
+//
+// Português:
+//
+//  Codifica e escreve a mensagem quando o nível está habilitado
+func (e *logger) write(level LogLevel, msg string, fields []interface{}) {
+	if level < e.level {
+		return
+	}
+
+	var buffer bytes.Buffer
+	var now = time.Now().UTC().Format(time.RFC3339Nano)
+
+	switch e.encoder {
+	case LogEncoderJSON:
+		buffer.WriteString(`{"time":`)
+		buffer.WriteString(strconv.Quote(now))
+		buffer.WriteString(`,"level":`)
+		buffer.WriteString(strconv.Quote(level.String()))
+		buffer.WriteString(`,"msg":`)
+		buffer.WriteString(jsonValue(msg))
+		for i := 0; i < len(fields); i += 2 {
+			buffer.WriteString(",")
+			buffer.WriteString(jsonValue(logFieldKey(fields, i)))
+			buffer.WriteString(":")
+			buffer.WriteString(jsonValue(logFieldValue(fields, i)))
+		}
+		buffer.WriteString("}\n")
+
+	default:
+		buffer.WriteString(now)
+		buffer.WriteString(" [")
+		buffer.WriteString(strings.ToUpper(level.String()))
+		buffer.WriteString("] ")
+		buffer.WriteString(msg)
+		for i := 0; i < len(fields); i += 2 {
+			buffer.WriteString(" ")
+			buffer.WriteString(logFieldKey(fields, i))
+			buffer.WriteString("=")
+			buffer.WriteString(textValue(logFieldValue(fields, i)))
+		}
+		buffer.WriteString("\n")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	_, _ = e.writer.Write(buffer.Bytes())
+}
+
+// logFieldKey
+//
+// English:
+//
+//  Returns the key of the pair that starts at index i
+//
+// Português:
+//
+//  Retorna a chave do par que começa no índice i
+func logFieldKey(fields []interface{}, i int) (key string) {
+	var ok bool
+	if key, ok = fields[i].(string); ok == false {
+		key = fmt.Sprint(fields[i])
+	}
+
+	return
+}
+
+// logFieldValue
+//
+// English:
+//
+//  Returns the value of the pair that starts at index i. A key without value, at the end of the
+//  list, receives "(missing)"
+//
+// Português:
+//
+//  Retorna o valor do par que começa no índice i. Uma chave sem valor, no fim da lista, recebe
+//  "(missing)"
+func logFieldValue(fields []interface{}, i int) (value interface{}) {
+	if i+1 >= len(fields) {
+		return "(missing)"
+	}
+
+	value = fields[i+1]
+	if err, ok := value.(error); ok == true {
+		value = err.Error()
+	} else if stringer, ok := value.(fmt.Stringer); ok == true {
+		value = stringer.String()
+	}
+
+	return
+}
+
+// jsonValue
+//
+// English:
+//
+//  Encodes the value as JSON, falling back to the text of the value
+//
+// Português:
+//
+//  Codifica o valor como JSON, recorrendo ao texto do valor
+func jsonValue(value interface{}) string {
+	var data, err = json.Marshal(value)
+	if err != nil {
+		return strconv.Quote(fmt.Sprint(value))
+	}
+
+	return string(data)
+}
+
+// textValue
+//
+// English:
+//
+//  Formats the value, quoting the texts with spaces, quotes or an equal sign
+//
+// Português:
+//
+//  Formata o valor, colocando entre aspas os textos com espaços, aspas ou sinal de igual
+func textValue(value interface{}) string {
+	var text = fmt.Sprint(value)
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
+
+// memberlistLogWriter
+//
+// English:
+//
+//  io.Writer given to the log.Logger of memberlist. Converts the "[LEVEL] memberlist: message"
+//  lines into calls to Logger
+//
+// Português:
+//
+//  io.Writer entregue ao log.Logger da memberlist. Converte as linhas
+//  "[NÍVEL] memberlist: mensagem" em chamadas ao Logger
+type memberlistLogWriter struct {
+	logger Logger
+}
+
+func (e *memberlistLogWriter) Write(data []byte) (n int, err error) {
+	n = len(data)
+
+	var line = strings.TrimSpace(string(data))
+	var write = e.logger.Info
+	if strings.HasPrefix(line, "[") && strings.Contains(line, "]") {
+		var end = strings.Index(line, "]")
+		switch line[1:end] {
+		case "DEBUG":
+			write = e.logger.Debug
+		case "WARN":
+			write = e.logger.Warn
+		case "ERR", "ERROR":
+			write = e.logger.Error
+		}
+		line = strings.TrimSpace(line[end+1:])
+	}
+
+	line = strings.TrimPrefix(line, "memberlist: ")
+	write(line, "component", "memberlist")
+	return
+}
@@ -0,0 +1,121 @@
+package iotmaker_docker_builder_demo
+
+// MemberState
+//
+// English:
+//
+//  State of a member of the cluster returned by Server.Members()
+//
+// Português:
+//
+//  Estado de um membro do cluster retornado por Server.Members()
+type MemberState int
+
+const (
+	// MemberAlive
+	//
+	// English: the member answers the failure detector
+	//
+	// Português: o membro responde ao detector de falhas
+	MemberAlive MemberState = iota
+
+	// MemberLeaving
+	//
+	// English: the member called Shutdown() and is leaving the cluster
+	//
+	// Português: o membro chamou Shutdown() e está saindo do cluster
+	MemberLeaving
+)
+
+// String
+//
+// English:
+//
+//  Returns the name of the state
+//
+// Português:
+//
+//  Retorna o nome do estado
+func (e MemberState) String() string {
+	switch e {
+	case MemberAlive:
+		return "alive"
+	case MemberLeaving:
+		return "leaving"
+	}
+
+	return "unknown"
+}
+
+// Member
+//
+// English:
+//
+//  Snapshot of a member of the cluster
+//
+//   Fields:
+//     Name: name of the node
+//     ID: logical ID of the node, kept between restarts
+//     Address: IP address of the node, IPv6 addresses without brackets
+//     Family: family of the address of the node
+//     Port: synchronism port of the node
+//     State: state of the node
+//     Ready: true if the node published itself as ready
+//     Live: true if the node published itself as alive by its liveness checks
+//     Meta: raw metadata published by the node
+//     GrpcPort: port of the gRPC server of the node
+//     Tags: tags published by the node, see Server.SetTag(). Each copy of the member has its own map
+//
+// Português:
+//
+//  Cópia de um membro do cluster
+//
+//   Campos:
+//     Name: nome do node
+//     ID: ID lógico do node, mantido entre reinícios
+//     Address: endereço IP do node, endereços IPv6 sem colchetes
+//     Family: família do endereço do node
+//     Port: porta de sincronismo do node
+//     State: estado do node
+//     Ready: true se o node se publicou como pronto
+//     Live: true se o node se publicou como vivo pelas suas verificações de vida
+//     Meta: metadados brutos publicados pelo node
+//     GrpcPort: porta do servidor gRPC do node
+//     Tags: tags publicadas pelo node, veja Server.SetTag(). Cada cópia do membro tem o seu próprio
+//           mapa
+type Member struct {
+	Name     string
+	ID       string
+	Address  string
+	Family   AddressFamily
+	Port     int
+	State    MemberState
+	Ready    bool
+	Live     bool
+	Meta     []byte
+	GrpcPort int
+	Tags     map[string]string
+}
+
+// copy
+//
+// English:
+//
+//  Returns a copy of the member that doesn't share Meta and Tags with the original
+//
+// Português:
+//
+//  Retorna uma cópia do membro que não compartilha Meta e Tags com o original
+func (e Member) copy() (member Member) {
+	member = e
+	member.Meta = append([]byte{}, e.Meta...)
+
+	if e.Tags != nil {
+		member.Tags = make(map[string]string, len(e.Tags))
+		for key, value := range e.Tags {
+			member.Tags[key] = value
+		}
+	}
+
+	return
+}
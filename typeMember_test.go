@@ -0,0 +1,42 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"testing"
+)
+
+func TestMemberCopy(t *testing.T) {
+	var tests = []struct {
+		name   string
+		member Member
+	}{
+		{name: "with tags", member: Member{Name: "a", Meta: []byte("meta"), Tags: map[string]string{TagRole: "worker"}}},
+		{name: "without tags", member: Member{Name: "a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var copied = test.member.copy()
+			if copied.Name != test.member.Name || string(copied.Meta) != string(test.member.Meta) || len(copied.Tags) != len(test.member.Tags) {
+				t.Fatalf("copy() = %+v, want %+v", copied, test.member)
+			}
+
+			if test.member.Tags == nil && copied.Tags != nil {
+				t.Fatal("copy() created a map for a member without tags")
+			}
+
+			if len(copied.Meta) != 0 {
+				copied.Meta[0] = 'X'
+			}
+			if copied.Tags != nil {
+				copied.Tags[TagRole] = "changed"
+			}
+
+			if len(test.member.Meta) != 0 && string(test.member.Meta) != "meta" {
+				t.Fatal("copy() shares Meta with the original")
+			}
+			if test.member.Tags != nil && test.member.Tags[TagRole] != "worker" {
+				t.Fatal("copy() shares Tags with the original")
+			}
+		})
+	}
+}
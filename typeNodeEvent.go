@@ -0,0 +1,124 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"time"
+)
+
+// NodeEventType
+//
+// English:
+//
+//  Type of the membership change delivered by Server.SubscribeNodeEvents()
+//
+// Português:
+//
+//  Tipo da mudança de membros entregue por Server.SubscribeNodeEvents()
+type NodeEventType int
+
+const (
+	// NodeJoined
+	//
+	// English: a node was detected for the first time or came back after leaving the cluster
+	//
+	// Português: um node foi detectado pela primeira vez ou voltou depois de sair do cluster
+	NodeJoined NodeEventType = iota
+
+	// NodeLeft
+	//
+	// English: a node left the cluster gracefully, calling Shutdown()
+	//
+	// Português: um node saiu do cluster de forma ordenada, chamando Shutdown()
+	NodeLeft
+
+	// NodeFailed
+	//
+	// English: a node was declared dead by the failure detector
+	//
+	// Português: um node foi declarado morto pelo detector de falhas
+	NodeFailed
+
+	// NodeAddressChanged
+	//
+	// English: a known node, by its ID or by its name, came back with a different address, for
+	// example, after a container restart
+	//
+	// Português: um node conhecido, pelo seu ID ou pelo seu nome, voltou com um endereço diferente, por
+	// exemplo, depois de reiniciar o container
+	NodeAddressChanged
+
+	// NodeUpdated
+	//
+	// English: a node changed its metadata, for example, its tags or its readiness
+	//
+	// Português: um node mudou os seus metadados, por exemplo, as suas tags ou a sua prontidão
+	NodeUpdated
+)
+
+// String
+//
+// English:
+//
+//  Returns the name of the event type
+//
+// Português:
+//
+//  Retorna o nome do tipo de evento
+func (e NodeEventType) String() string {
+	switch e {
+	case NodeJoined:
+		return "joined"
+	case NodeLeft:
+		return "left"
+	case NodeFailed:
+		return "failed"
+	case NodeAddressChanged:
+		return "address changed"
+	case NodeUpdated:
+		return "updated"
+	}
+
+	return "unknown"
+}
+
+// NodeEvent
+//
+// English:
+//
+//  Membership change of a node in the cluster
+//
+//   Fields:
+//     Type: type of change
+//     Name: name of the node
+//     ID: logical ID of the node, kept between restarts, see Server.SetNodeIDPath()
+//     Address: current IP address of the node, IPv6 addresses without brackets
+//     Family: family of the current address of the node
+//     PreviousAddress: previous IP address of the node, only for NodeAddressChanged
+//     Meta: metadata published by the node
+//     Tags: tags published by the node, must not be changed
+//     Time: time at which the change was detected
+//
+// Português:
+//
+//  Mudança de um node do cluster
+//
+//   Campos:
+//     Type: tipo da mudança
+//     Name: nome do node
+//     ID: ID lógico do node, mantido entre reinícios, veja Server.SetNodeIDPath()
+//     Address: endereço IP atual do node, endereços IPv6 sem colchetes
+//     Family: família do endereço atual do node
+//     PreviousAddress: endereço IP anterior do node, apenas para NodeAddressChanged
+//     Meta: metadados publicados pelo node
+//     Tags: tags publicadas pelo node, não devem ser alteradas
+//     Time: momento em que a mudança foi detectada
+type NodeEvent struct {
+	Type            NodeEventType
+	Name            string
+	ID              string
+	Address         string
+	Family          AddressFamily
+	PreviousAddress string
+	Meta            []byte
+	Tags            map[string]string
+	Time            time.Time
+}
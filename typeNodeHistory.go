@@ -0,0 +1,446 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NodeStatus
+//
+// English:
+//
+//  Last known status of a node in the node history
+//
+// Português:
+//
+//  Último estado conhecido de um node no histórico de nodes
+type NodeStatus int
+
+const (
+	// NodeStatusAlive
+	//
+	// English: the node is a member of the cluster
+	//
+	// Português: o node é membro do cluster
+	NodeStatusAlive NodeStatus = iota
+
+	// NodeStatusLeft
+	//
+	// English: tombstone of a node that left the cluster gracefully
+	//
+	// Português: lápide de um node que saiu do cluster de forma ordenada
+	NodeStatusLeft
+
+	// NodeStatusFailed
+	//
+	// English: tombstone of a node declared dead by the failure detector
+	//
+	// Português: lápide de um node declarado morto pelo detector de falhas
+	NodeStatusFailed
+
+	// NodeStatusGone
+	//
+	// English: tombstone of a node marked as permanently gone by Server.MarkNodeGone()
+	//
+	// Português: lápide de um node marcado como removido em definitivo por Server.MarkNodeGone()
+	NodeStatusGone
+)
+
+// String
+//
+// English:
+//
+//  Returns the name of the status
+//
+// Português:
+//
+//  Retorna o nome do estado
+func (e NodeStatus) String() string {
+	switch e {
+	case NodeStatusAlive:
+		return "alive"
+	case NodeStatusLeft:
+		return "left"
+	case NodeStatusFailed:
+		return "failed"
+	case NodeStatusGone:
+		return "gone"
+	}
+
+	return "unknown"
+}
+
+// MarshalText
+//
+// English:
+//
+//  Writes the status by name in the persisted history
+//
+// Português:
+//
+//  Escreve o estado pelo nome no histórico persistido
+func (e NodeStatus) MarshalText() (text []byte, err error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText
+//
+// English:
+//
+//  Reads the status written by MarshalText()
+//
+// Português:
+//
+//  Lê o estado escrito por MarshalText()
+func (e *NodeStatus) UnmarshalText(text []byte) (err error) {
+	for _, status := range []NodeStatus{NodeStatusAlive, NodeStatusLeft, NodeStatusFailed, NodeStatusGone} {
+		if status.String() == string(text) {
+			*e = status
+			return
+		}
+	}
+
+	return fmt.Errorf("unknown node status: %q", text)
+}
+
+// NodeRecord
+//
+// English:
+//
+//  Entry of the node history
+//
+//   Fields:
+//     Name: name of the node
+//     ID: logical ID of the node, see Server.SetNodeIDPath()
+//     Address: last known IP address of the node
+//     Status: last known status of the node
+//     Since: time of the last status change
+//     LastSeen: last time the node was seen as a member
+//
+// Português:
+//
+//  Entrada do histórico de nodes
+//
+//   Campos:
+//     Name: nome do node
+//     ID: ID lógico do node, veja Server.SetNodeIDPath()
+//     Address: último endereço IP conhecido do node
+//     Status: último estado conhecido do node
+//     Since: momento da última mudança de estado
+//     LastSeen: última vez em que o node foi visto como membro
+type NodeRecord struct {
+	Name     string     `json:"name"`
+	ID       string     `json:"id,omitempty"`
+	Address  string     `json:"address"`
+	Status   NodeStatus `json:"status"`
+	Since    time.Time  `json:"since"`
+	LastSeen time.Time  `json:"lastSeen"`
+}
+
+// nodeHistory
+//
+// English:
+//
+//  Lifecycle of every node seen by this instance. The nodes that left keep a tombstone until the
+//  reap TTL expires
+//
+// Português:
+//
+//  Ciclo de vida de todos os nodes vistos por esta instância. Os nodes que saíram mantêm uma lápide
+//  até o TTL de remoção expirar
+type nodeHistory struct {
+	mutex   sync.Mutex
+	records map[string]NodeRecord
+	changed bool
+}
+
+// newNodeHistory
+//
+// English:
+//
+//  Returns an empty history
+//
+// Português:
+//
+//  Retorna um histórico vazio
+func newNodeHistory() (history *nodeHistory) {
+	return &nodeHistory{
+		records: make(map[string]NodeRecord),
+	}
+}
+
+// alive
+//
+// English:
+//
+//  Records the node as a member.
+//
+//  A node with the ID of a record of another name is the same node under a new name, the old record
+//  is replaced. A node with the name of a record of another ID is a new node
+//
+//   Output:
+//     previous: record before this call
+//     sameNode: true if the record belongs to the same node, false for a new node
+//
+// Português:
+//
+//  Registra o node como membro.
+//
+//  Um node com o ID de um registro de outro nome é o mesmo node com um novo nome, o registro antigo é
+//  trocado. Um node com o nome de um registro de outro ID é um novo node
+//
+//   Saída:
+//     previous: registro antes desta chamada
+//     sameNode: true se o registro pertence ao mesmo node, false para um novo node
+func (e *nodeHistory) alive(name, id, address string, now time.Time) (previous NodeRecord, sameNode bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var found bool
+	previous, found = e.records[name]
+	if id != "" && previous.ID != id {
+		for otherName, other := range e.records {
+			if other.ID == id {
+				previous, found = other, true
+				delete(e.records, otherName)
+				break
+			}
+		}
+	}
+
+	sameNode = found == true && (previous.ID == "" || id == "" || previous.ID == id)
+
+	var record = previous
+	if sameNode == false || record.Status != NodeStatusAlive {
+		record.Since = now
+	}
+	record.Name = name
+	record.ID = id
+	record.Address = address
+	record.Status = NodeStatusAlive
+	record.LastSeen = now
+
+	e.records[name] = record
+	e.changed = true
+	return
+}
+
+// down
+//
+// English:
+//
+//  Replaces the record of the node by a tombstone with the status given. Unknown nodes are ignored,
+//  for example, the old name of a node that came back with a new name
+//
+// Português:
+//
+//  Troca o registro do node por uma lápide com o estado informado. Nodes desconhecidos são
+//  ignorados, por exemplo, o nome antigo de um node que voltou com um novo nome
+func (e *nodeHistory) down(name string, status NodeStatus, now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var record, found = e.records[name]
+	if found == false {
+		return
+	}
+
+	if record.Status == NodeStatusAlive {
+		record.LastSeen = now
+	}
+	record.Status = status
+	record.Since = now
+
+	e.records[name] = record
+	e.changed = true
+}
+
+// get
+//
+// English:
+//
+//  Returns the record of the node
+//
+// Português:
+//
+//  Retorna o registro do node
+func (e *nodeHistory) get(name string) (record NodeRecord, found bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	record, found = e.records[name]
+	return
+}
+
+// reap
+//
+// English:
+//
+//  Removes the tombstones older than ttl
+//
+//   Output:
+//     removed: names of the nodes removed
+//
+// Português:
+//
+//  Remove as lápides mais antigas que ttl
+//
+//   Saída:
+//     removed: nomes dos nodes removidos
+func (e *nodeHistory) reap(ttl time.Duration, now time.Time) (removed []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for name, record := range e.records {
+		if record.Status == NodeStatusAlive || now.Sub(record.Since) < ttl {
+			continue
+		}
+
+		delete(e.records, name)
+		removed = append(removed, name)
+	}
+
+	if len(removed) != 0 {
+		e.changed = true
+	}
+
+	return
+}
+
+// snapshot
+//
+// English:
+//
+//  Returns a copy of the records ordered by name
+//
+// Português:
+//
+//  Retorna uma cópia dos registros ordenada pelo nome
+func (e *nodeHistory) snapshot() (records []NodeRecord) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	records = make([]NodeRecord, 0, len(e.records))
+	for _, record := range e.records {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return
+}
+
+// save
+//
+// English:
+//
+//  Writes the history as JSON when it changed since the last save. The file is replaced
+//  atomically, by a temporary file and a rename
+//
+// Português:
+//
+//  Escreve o histórico como JSON quando ele mudou desde a última gravação. O arquivo é trocado de
+//  forma atômica, por um arquivo temporário e uma renomeação
+func (e *nodeHistory) save(path string, now time.Time) (err error) {
+	e.mutex.Lock()
+	if e.changed == false {
+		e.mutex.Unlock()
+		return
+	}
+
+	var records = make([]NodeRecord, 0, len(e.records))
+	for name, record := range e.records {
+		if record.Status == NodeStatusAlive {
+			record.LastSeen = now
+			e.records[name] = record
+		}
+		records = append(records, record)
+	}
+	e.changed = false
+	e.mutex.Unlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+	var data []byte
+	data, err = json.Marshal(records)
+	if err != nil {
+		return
+	}
+
+	var file *os.File
+	file, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Close()
+	} else {
+		_ = file.Close()
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+		e.mutex.Lock()
+		e.changed = true
+		e.mutex.Unlock()
+	}
+
+	return
+}
+
+// load
+//
+// English:
+//
+//  Reads the history written by save(). A missing file is not an error.
+//
+//  The nodes recorded as members become failed since their last time seen, because this instance
+//  doesn't know what happened to them while it was down
+//
+// Português:
+//
+//  Lê o histórico escrito por save(). Um arquivo inexistente não é um erro.
+//
+//  Os nodes registrados como membros passam a falhos desde a última vez em que foram vistos, pois
+//  esta instância não sabe o que aconteceu com eles enquanto estava desligada
+func (e *nodeHistory) load(path string) (err error) {
+	var data []byte
+	data, err = os.ReadFile(path)
+	if os.IsNotExist(err) == true {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	var records []NodeRecord
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, record := range records {
+		if record.Status == NodeStatusAlive {
+			record.Status = NodeStatusFailed
+			record.Since = record.LastSeen
+		}
+
+		e.records[record.Name] = record
+	}
+
+	return
+}
@@ -0,0 +1,178 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNodeHistoryAlive(t *testing.T) {
+	var start = time.Unix(1000, 0)
+
+	var tests = []struct {
+		name     string
+		prepare  func(history *nodeHistory)
+		node     string
+		id       string
+		sameNode bool
+		previous string
+	}{
+		{name: "new node", node: "a", id: "1", sameNode: false},
+		{
+			name:     "restart with the same ID",
+			prepare:  func(history *nodeHistory) { history.alive("a", "1", "10.0.0.1", start) },
+			node:     "a",
+			id:       "1",
+			sameNode: true,
+			previous: "a",
+		},
+		{
+			name:     "same name with another ID",
+			prepare:  func(history *nodeHistory) { history.alive("a", "1", "10.0.0.1", start) },
+			node:     "a",
+			id:       "2",
+			sameNode: false,
+			previous: "a",
+		},
+		{
+			name:     "same ID with another name",
+			prepare:  func(history *nodeHistory) { history.alive("a", "1", "10.0.0.1", start) },
+			node:     "b",
+			id:       "1",
+			sameNode: true,
+			previous: "a",
+		},
+		{
+			name:     "record without ID",
+			prepare:  func(history *nodeHistory) { history.alive("a", "", "10.0.0.1", start) },
+			node:     "a",
+			id:       "1",
+			sameNode: true,
+			previous: "a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var history = newNodeHistory()
+			if test.prepare != nil {
+				test.prepare(history)
+			}
+
+			var previous, sameNode = history.alive(test.node, test.id, "10.0.0.2", start.Add(time.Minute))
+			if sameNode != test.sameNode || previous.Name != test.previous {
+				t.Fatalf("alive() = %q, %v, want %q, %v", previous.Name, sameNode, test.previous, test.sameNode)
+			}
+
+			var records = history.snapshot()
+			if len(records) != 1 || records[0].Name != test.node || records[0].ID != test.id || records[0].Status != NodeStatusAlive {
+				t.Fatalf("snapshot() = %+v", records)
+			}
+		})
+	}
+}
+
+func TestNodeHistoryDownAndReap(t *testing.T) {
+	var start = time.Unix(1000, 0)
+
+	var history = newNodeHistory()
+	history.alive("left", "1", "10.0.0.1", start)
+	history.alive("failed", "2", "10.0.0.2", start)
+	history.alive("alive", "3", "10.0.0.3", start)
+	history.down("left", NodeStatusLeft, start.Add(time.Minute))
+	history.down("failed", NodeStatusFailed, start.Add(2*time.Minute))
+	history.down("unknown", NodeStatusFailed, start.Add(2*time.Minute))
+
+	var tests = []struct {
+		name   string
+		status NodeStatus
+		since  time.Time
+	}{
+		{name: "left", status: NodeStatusLeft, since: start.Add(time.Minute)},
+		{name: "failed", status: NodeStatusFailed, since: start.Add(2 * time.Minute)},
+		{name: "alive", status: NodeStatusAlive, since: start},
+	}
+
+	for _, test := range tests {
+		var record, found = history.get(test.name)
+		if found == false || record.Status != test.status || record.Since.Equal(test.since) == false {
+			t.Fatalf("get(%q) = %+v, %v, want %v since %v", test.name, record, found, test.status, test.since)
+		}
+	}
+
+	if _, found := history.get("unknown"); found == true {
+		t.Fatal("down() created a record of an unknown node")
+	}
+
+	var removed = history.reap(90*time.Second, start.Add(3*time.Minute))
+	if len(removed) != 1 || removed[0] != "left" {
+		t.Fatalf("reap() = %v, want [left]", removed)
+	}
+
+	if removed = history.reap(time.Nanosecond, start.Add(time.Hour)); len(removed) != 1 || removed[0] != "failed" {
+		t.Fatalf("reap() = %v, want [failed], alive nodes are never reaped", removed)
+	}
+}
+
+func TestNodeHistoryPersistence(t *testing.T) {
+	var start = time.Unix(1000, 0).UTC()
+	var path = filepath.Join(t.TempDir(), "history.json")
+
+	var history = newNodeHistory()
+	history.alive("alive", "1", "10.0.0.1", start)
+	history.alive("left", "2", "10.0.0.2", start)
+	history.down("left", NodeStatusLeft, start.Add(time.Minute))
+
+	var err = history.save(path, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded = newNodeHistory()
+	if err = loaded.load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name   string
+		status NodeStatus
+		since  time.Time
+	}{
+		// English: an alive node of the previous process is failed since it was last seen
+		// Português: um node vivo do processo anterior está falho desde quando foi visto por último
+		{name: "alive", status: NodeStatusFailed, since: start.Add(2 * time.Minute)},
+		{name: "left", status: NodeStatusLeft, since: start.Add(time.Minute)},
+	}
+
+	for _, test := range tests {
+		var record, found = loaded.get(test.name)
+		if found == false || record.Status != test.status || record.Since.Equal(test.since) == false {
+			t.Fatalf("get(%q) = %+v, %v, want %v since %v", test.name, record, found, test.status, test.since)
+		}
+	}
+
+	// English: without changes, save() doesn't write the file again
+	// Português: sem alterações, save() não escreve o arquivo novamente
+	if err = os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err = history.save(path, start.Add(3*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(path); os.IsNotExist(err) == false {
+		t.Fatalf("save() without changes wrote the file, stat error = %v", err)
+	}
+
+	var missing = newNodeHistory()
+	if err = missing.load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Fatalf("load() of a missing file = %v, want nil", err)
+	}
+
+	if err = os.WriteFile(path, []byte(`[{"name":"a","status":"zombie"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = missing.load(path); err == nil {
+		t.Fatal("load() accepted an unknown status")
+	}
+}
@@ -0,0 +1,227 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/memberlist"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	//kNodeMetaReservedIDSize
+	//
+	// English:
+	//
+	// Size of the ID reserved in the node metadata while the ID is shorter, or not loaded yet, so the
+	// tags defined before Init() leave room for the ID loaded by Init().
+	//
+	// Português:
+	//
+	// Tamanho do ID reservado nos metadados do node enquanto o ID é menor, ou ainda não foi carregado,
+	// assim as tags definidas antes de Init() deixam espaço para o ID carregado por Init().
+	kNodeMetaReservedIDSize = 64
+)
+
+// nodeMeta
+//
+// English:
+//
+//  Metadata published by the node to the other members of the cluster
+//
+//   Fields:
+//     Leaving: true when the node is leaving the cluster by Shutdown()
+//     GrpcPort: port of the gRPC server of the SyncInstances service
+//     Ready: true if all readiness and liveness checks of the node passed
+//     Live: true if all liveness checks of the node passed
+//     LeaderPriority: priority of the node in the leader election
+//     RingWeight: weight of the node in the consistent hash ring
+//
+//   Note:
+//     * memberlist v0.3.0 does not fill in memberlist.Node.State, so the members can only tell a
+//       graceful leave from a failure by the metadata sent before the leave message.
+//
+// Português:
+//
+//  Metadados publicados pelo node para os demais membros do cluster
+//
+//   Campos:
+//     Leaving: true quando o node está saindo do cluster por Shutdown()
+//     GrpcPort: porta do servidor gRPC do serviço SyncInstances
+//     Ready: true se todas as verificações de prontidão e de vida do node passaram
+//     Live: true se todas as verificações de vida do node passaram
+//     LeaderPriority: prioridade do node na eleição de líder
+//     RingWeight: peso do node no anel de hash consistente
+//
+//   Nota:
+//     * A memberlist v0.3.0 não preenche memberlist.Node.State, por isto, os membros só conseguem
+//       diferenciar uma saída ordenada de uma falha pelos metadados enviados antes da mensagem de saída.
+type nodeMeta struct {
+	ID             string `json:"i,omitempty"`
+	Leaving        bool   `json:"l,omitempty"`
+	GrpcPort       int    `json:"g,omitempty"`
+	Ready          bool   `json:"r,omitempty"`
+	Live           bool   `json:"v,omitempty"`
+	LeaderPriority int    `json:"p,omitempty"`
+	RingWeight     int    `json:"w,omitempty"`
+
+	// English: replaced as a whole, never changed in place, because getNodeMeta() returns a copy that
+	// shares the map
+	//
+	// Português: trocado por inteiro, nunca alterado no lugar, pois getNodeMeta() retorna uma cópia que
+	// compartilha o mapa
+	Tags map[string]string `json:"t,omitempty"`
+}
+
+// encode
+//
+// English:
+//
+//  Encodes the metadata for memberlist
+//
+// Português:
+//
+//  Codifica os metadados para a memberlist
+func (e nodeMeta) encode() (data []byte) {
+	data, _ = json.Marshal(e)
+	return
+}
+
+// fits
+//
+// English:
+//
+//  Returns true if the encoded metadata fits in limit bytes even when the fixed fields reach their
+//  largest values, so a later change of Leaving, GrpcPort, Ready, Live, LeaderPriority or RingWeight
+//  never exceeds the limit
+//
+// Português:
+//
+//  Retorna true se os metadados codificados cabem em limit bytes mesmo quando os campos fixos chegam
+//  aos seus maiores valores, assim uma alteração posterior de Leaving, GrpcPort, Ready, Live,
+//  LeaderPriority ou RingWeight nunca excede o limite
+func (e nodeMeta) fits(limit int) (fits bool) {
+	var largest = e
+	if len(largest.ID) < kNodeMetaReservedIDSize {
+		largest.ID = strings.Repeat("0", kNodeMetaReservedIDSize)
+	}
+	largest.Leaving = true
+	largest.GrpcPort = math.MaxUint16
+	largest.Ready = true
+	largest.Live = true
+	largest.LeaderPriority = math.MinInt
+	largest.RingWeight = math.MinInt
+
+	return len(largest.encode()) <= limit
+}
+
+// truncate
+//
+// English:
+//
+//  Returns the metadata without the tags needed to fit in limit bytes. The tags are removed in the
+//  reverse order of their keys and the other fields are always kept
+//
+//   Output:
+//     meta: metadata that fits in limit bytes, with a new map when tags were removed
+//     dropped: keys of the removed tags
+//
+// Português:
+//
+//  Retorna os metadados sem as tags necessárias para caber em limit bytes. As tags são removidas na
+//  ordem inversa das suas chaves e os demais campos são sempre mantidos
+//
+//   Saída:
+//     meta: metadados que cabem em limit bytes, com um novo mapa quando tags foram removidas
+//     dropped: chaves das tags removidas
+func (e nodeMeta) truncate(limit int) (meta nodeMeta, dropped []string) {
+	meta = e
+	if len(meta.encode()) <= limit {
+		return
+	}
+
+	var keys = make([]string, 0, len(e.Tags))
+	meta.Tags = make(map[string]string, len(e.Tags))
+	for key, value := range e.Tags {
+		keys = append(keys, key)
+		meta.Tags[key] = value
+	}
+	sort.Strings(keys)
+
+	for i := len(keys) - 1; i >= 0 && len(meta.encode()) > limit; i -= 1 {
+		delete(meta.Tags, keys[i])
+		dropped = append(dropped, keys[i])
+	}
+
+	return
+}
+
+// decodeNodeMeta
+//
+// English:
+//
+//  Decodes the metadata received from memberlist. Invalid metadata results in an empty value
+//
+// Português:
+//
+//  Decodifica os metadados recebidos da memberlist. Metadados inválidos resultam em um valor vazio
+func decodeNodeMeta(data []byte) (meta nodeMeta) {
+	if len(data) == 0 {
+		return
+	}
+
+	_ = json.Unmarshal(data, &meta)
+	return
+}
+
+// getNodeMeta
+//
+// English:
+//
+//  Returns a copy of the metadata of the node
+//
+// Português:
+//
+//  Retorna uma cópia dos metadados do node
+func (e *Server) getNodeMeta() (meta nodeMeta) {
+	e.nodeMetaMutex.Lock()
+	defer e.nodeMetaMutex.Unlock()
+
+	return e.nodeMeta
+}
+
+// updateNodeMeta
+//
+// English:
+//
+//  Changes the metadata of the node. The change is only sent to the other members after
+//  memberlist.UpdateNode()
+//
+//   Output:
+//     err: ErrNodeMetaTooLarge when the change exceeds the memberlist limit. The change is kept and
+//          the tags that don't fit are removed and logged, the other fields are never dropped
+//
+// Português:
+//
+//  Altera os metadados do node. A alteração só é enviada aos demais membros depois de
+//  memberlist.UpdateNode()
+//
+//   Saída:
+//     err: ErrNodeMetaTooLarge quando a alteração excede o limite da memberlist. A alteração é
+//          mantida e as tags que não cabem são removidas e registradas no log, os demais campos nunca
+//          são descartados
+func (e *Server) updateNodeMeta(update func(meta *nodeMeta)) (err error) {
+	e.nodeMetaMutex.Lock()
+	defer e.nodeMetaMutex.Unlock()
+
+	update(&e.nodeMeta)
+
+	var dropped []string
+	e.nodeMeta, dropped = e.nodeMeta.truncate(memberlist.MetaMaxSize)
+	if len(dropped) != 0 {
+		e.getLogger().Warn("node metadata exceeds the memberlist limit, tags dropped", "tags", strings.Join(dropped, ","))
+		err = ErrNodeMetaTooLarge
+	}
+
+	return
+}
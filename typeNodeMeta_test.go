@@ -0,0 +1,110 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNodeMetaFits(t *testing.T) {
+	var tags = func(count, size int) map[string]string {
+		var tags = make(map[string]string)
+		for i := 0; i != count; i += 1 {
+			tags["tag"+strconv.Itoa(i)] = strings.Repeat("x", size)
+		}
+		return tags
+	}
+
+	var tests = []struct {
+		name  string
+		meta  nodeMeta
+		limit int
+		want  bool
+	}{
+		{name: "empty", meta: nodeMeta{}, limit: 512, want: true},
+		{name: "small tags", meta: nodeMeta{Tags: tags(4, 10)}, limit: 512, want: true},
+		{name: "tags over the limit", meta: nodeMeta{Tags: tags(10, 50)}, limit: 512, want: false},
+		{name: "no room for the fixed fields", meta: nodeMeta{ID: "id", Tags: tags(1, 10)}, limit: 100, want: false},
+		{name: "room for the largest ID", meta: nodeMeta{ID: "id"}, limit: 159, want: true},
+		{name: "no room for the largest ID", meta: nodeMeta{ID: "id"}, limit: 158, want: false},
+		{name: "ID longer than the reserved size", meta: nodeMeta{ID: strings.Repeat("i", kNodeMetaReservedIDSize+1)}, limit: 159, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if fits := test.meta.fits(test.limit); fits != test.want {
+				t.Fatalf("fits(%d) = %v, want %v, encoded in %d bytes", test.limit, fits, test.want, len(test.meta.encode()))
+			}
+		})
+	}
+}
+
+func TestNodeMetaTruncate(t *testing.T) {
+	var meta = nodeMeta{
+		ID:             "node-id",
+		GrpcPort:       40000,
+		Ready:          true,
+		Live:           true,
+		LeaderPriority: 7,
+		Tags: map[string]string{
+			"a": strings.Repeat("x", 100),
+			"b": strings.Repeat("x", 100),
+			"c": strings.Repeat("x", 100),
+		},
+	}
+	var size = len(meta.encode())
+
+	var tests = []struct {
+		name    string
+		limit   int
+		dropped []string
+	}{
+		{name: "fits", limit: size, dropped: nil},
+		{name: "one tag", limit: size - 1, dropped: []string{"c"}},
+		{name: "all tags", limit: 90, dropped: []string{"c", "b", "a"}},
+		{name: "smaller than the fixed fields", limit: 10, dropped: []string{"c", "b", "a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var truncated, dropped = meta.truncate(test.limit)
+			if strings.Join(dropped, ",") != strings.Join(test.dropped, ",") {
+				t.Fatalf("truncate(%d) dropped %v, want %v", test.limit, dropped, test.dropped)
+			}
+
+			if truncated.ID != meta.ID || truncated.GrpcPort != meta.GrpcPort || truncated.Ready != meta.Ready || truncated.LeaderPriority != meta.LeaderPriority {
+				t.Fatalf("truncate(%d) changed the fixed fields: %+v", test.limit, truncated)
+			}
+
+			if len(truncated.encode()) > test.limit && len(truncated.Tags) != 0 {
+				t.Fatalf("truncate(%d) kept tags over the limit: %d bytes", test.limit, len(truncated.encode()))
+			}
+
+			if len(meta.Tags) != 3 {
+				t.Fatalf("truncate(%d) changed the map of the original", test.limit)
+			}
+		})
+	}
+}
+
+func TestNodeMetaDecode(t *testing.T) {
+	var meta = nodeMeta{ID: "id", Leaving: true, GrpcPort: 1, Ready: true, Live: true, LeaderPriority: -3, RingWeight: 2, Tags: map[string]string{"k": "v"}}
+
+	var tests = []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "round trip", data: meta.encode(), want: string(meta.encode())},
+		{name: "empty", data: nil, want: "{}"},
+		{name: "invalid", data: []byte("not json"), want: "{}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(decodeNodeMeta(test.data).encode()); got != test.want {
+				t.Fatalf("decodeNodeMeta() encodes as %s, want %s", got, test.want)
+			}
+		})
+	}
+}
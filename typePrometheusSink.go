@@ -0,0 +1,453 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"fmt"
+	"github.com/armon/go-metrics"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prometheusMetricType
+//
+// English:
+//
+//  Type of a metric in the Prometheus text format
+//
+// Português:
+//
+//  Tipo de uma métrica no formato texto do Prometheus
+type prometheusMetricType string
+
+const (
+	kPrometheusGauge   prometheusMetricType = "gauge"
+	kPrometheusCounter prometheusMetricType = "counter"
+	kPrometheusSummary prometheusMetricType = "summary"
+)
+
+// prometheusSeries
+//
+// English:
+//
+//  Value of a metric for one set of labels
+//
+// Português:
+//
+//  Valor de uma métrica para um conjunto de rótulos
+type prometheusSeries struct {
+	labels string
+	value  float64
+	count  uint64
+}
+
+// prometheusMetric
+//
+// English:
+//
+//  All series of a metric
+//
+// Português:
+//
+//  Todas as séries de uma métrica
+type prometheusMetric struct {
+	metricType prometheusMetricType
+	series     map[string]*prometheusSeries
+}
+
+// prometheusSink
+//
+// English:
+//
+//  Implements metrics.MetricSink, keeping the last value of the gauges, the sum of the counters and
+//  the count and sum of the samples, and writes them in the Prometheus text format.
+//
+//  The samples are written as summaries without quantiles, the timings are in milliseconds
+//
+// Português:
+//
+//  Implementa metrics.MetricSink, guardando o último valor dos medidores, a soma dos contadores e a
+//  contagem e a soma das amostras, e as escreve no formato texto do Prometheus.
+//
+//  As amostras são escritas como sumários sem quantis, os tempos estão em milissegundos
+type prometheusSink struct {
+	mutex   sync.Mutex
+	metrics map[string]*prometheusMetric
+}
+
+// newPrometheusSink
+//
+// English:
+//
+//  Returns an empty sink
+//
+// Português:
+//
+//  Retorna um coletor vazio
+func newPrometheusSink() (sink *prometheusSink) {
+	return &prometheusSink{
+		metrics: make(map[string]*prometheusMetric),
+	}
+}
+
+func (e *prometheusSink) SetGauge(key []string, val float32) {
+	e.SetGaugeWithLabels(key, val, nil)
+}
+
+func (e *prometheusSink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.store(kPrometheusGauge, key, labels, func(series *prometheusSeries) {
+		series.value = float64(val)
+	})
+}
+
+func (e *prometheusSink) EmitKey(key []string, val float32) {
+	e.SetGaugeWithLabels(key, val, nil)
+}
+
+func (e *prometheusSink) IncrCounter(key []string, val float32) {
+	e.IncrCounterWithLabels(key, val, nil)
+}
+
+func (e *prometheusSink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.store(kPrometheusCounter, key, labels, func(series *prometheusSeries) {
+		series.value += float64(val)
+	})
+}
+
+func (e *prometheusSink) AddSample(key []string, val float32) {
+	e.AddSampleWithLabels(key, val, nil)
+}
+
+func (e *prometheusSink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.store(kPrometheusSummary, key, labels, func(series *prometheusSeries) {
+		series.value += float64(val)
+		series.count += 1
+	})
+}
+
+// store
+//
+// English:
+//
+//  Finds or creates the series of the metric and updates it
+//
+// Português:
+//
+//  Encontra ou cria a série da métrica e a atualiza
+func (e *prometheusSink) store(metricType prometheusMetricType, key []string, labels []metrics.Label, update func(series *prometheusSeries)) {
+	var name = prometheusName(key)
+	var labelsText = prometheusLabels(labels)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var metric, found = e.metrics[name]
+	if found == false {
+		metric = &prometheusMetric{metricType: metricType, series: make(map[string]*prometheusSeries)}
+		e.metrics[name] = metric
+	}
+
+	var series *prometheusSeries
+	series, found = metric.series[labelsText]
+	if found == false {
+		series = &prometheusSeries{labels: labelsText}
+		metric.series[labelsText] = series
+	}
+
+	update(series)
+}
+
+// write
+//
+// English:
+//
+//  Writes all metrics in the Prometheus text format, ordered by name
+//
+// Português:
+//
+//  Escreve todas as métricas no formato texto do Prometheus, ordenadas pelo nome
+func (e *prometheusSink) write(writer io.Writer) (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var names = make([]string, 0, len(e.metrics))
+	for name := range e.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var metric = e.metrics[name]
+		var seriesList = make([]*prometheusSeries, 0, len(metric.series))
+		for _, series := range metric.series {
+			seriesList = append(seriesList, series)
+		}
+		sort.Slice(seriesList, func(i, j int) bool { return seriesList[i].labels < seriesList[j].labels })
+
+		var rows = make([]string, 0, len(seriesList)*2+1)
+		rows = append(rows, fmt.Sprintf("# TYPE %v %v", name, metric.metricType))
+		for _, series := range seriesList {
+			if metric.metricType == kPrometheusSummary {
+				rows = append(rows, fmt.Sprintf("%v_sum%v %v", name, series.labels, prometheusValue(series.value)))
+				rows = append(rows, fmt.Sprintf("%v_count%v %v", name, series.labels, series.count))
+				continue
+			}
+
+			rows = append(rows, fmt.Sprintf("%v%v %v", name, series.labels, prometheusValue(series.value)))
+		}
+
+		_, err = io.WriteString(writer, strings.Join(rows, "\n")+"\n")
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// prometheusName
+//
+// English:
+//
+//  Converts a go-metrics key into a valid Prometheus metric name
+//
+// Português:
+//
+//  Converte uma chave do go-metrics em um nome de métrica válido do Prometheus
+func prometheusName(key []string) (name string) {
+	return prometheusSanitize(strings.Join(key, "_"))
+}
+
+// prometheusLabels
+//
+// English:
+//
+//  Converts the labels into the {name="value",...} format, ordered by name
+//
+// Português:
+//
+//  Converte os rótulos para o formato {nome="valor",...}, ordenados pelo nome
+func prometheusLabels(labels []metrics.Label) (text string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	var pairs = make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, prometheusSanitize(label.Name)+"="+strconv.Quote(label.Value))
+	}
+	sort.Strings(pairs)
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// prometheusSanitize
+//
+// English:
+//
+//  Replaces the characters not allowed in Prometheus names by _
+//
+// Português:
+//
+//  Troca os caracteres não permitidos em nomes do Prometheus por _
+func prometheusSanitize(name string) string {
+	return strings.Map(func(char rune) rune {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_' {
+			return char
+		}
+
+		return '_'
+	}, name)
+}
+
+// prometheusValue
+//
+// English:
+//
+//  Formats a value in the Prometheus text format
+//
+// Português:
+//
+//  Formata um valor no formato texto do Prometheus
+func prometheusValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// prometheusFanoutSink
+//
+// English:
+//
+//  Global go-metrics sink installed by EnableMemberlistMetrics(). memberlist only writes to the
+//  global go-metrics instance, so its metrics are copied to the sink of the application and to the
+//  sinks of the running Server
+//
+// Português:
+//
+//  Coletor global do go-metrics instalado por EnableMemberlistMetrics(). A memberlist só escreve na
+//  instância global do go-metrics, por isto as suas métricas são copiadas para o coletor da
+//  aplicação e para os coletores dos Server em execução
+type prometheusFanoutSink struct {
+	mutex       sync.RWMutex
+	application metrics.MetricSink
+	sinks       map[*prometheusSink]struct{}
+}
+
+func (e *prometheusFanoutSink) SetGauge(key []string, val float32) {
+	e.each(func(sink metrics.MetricSink) { sink.SetGauge(key, val) })
+}
+
+func (e *prometheusFanoutSink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.each(func(sink metrics.MetricSink) { sink.SetGaugeWithLabels(key, val, labels) })
+}
+
+func (e *prometheusFanoutSink) EmitKey(key []string, val float32) {
+	e.each(func(sink metrics.MetricSink) { sink.EmitKey(key, val) })
+}
+
+func (e *prometheusFanoutSink) IncrCounter(key []string, val float32) {
+	e.each(func(sink metrics.MetricSink) { sink.IncrCounter(key, val) })
+}
+
+func (e *prometheusFanoutSink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.each(func(sink metrics.MetricSink) { sink.IncrCounterWithLabels(key, val, labels) })
+}
+
+func (e *prometheusFanoutSink) AddSample(key []string, val float32) {
+	e.each(func(sink metrics.MetricSink) { sink.AddSample(key, val) })
+}
+
+func (e *prometheusFanoutSink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	e.each(func(sink metrics.MetricSink) { sink.AddSampleWithLabels(key, val, labels) })
+}
+
+// each
+//
+// English:
+//
+//  Calls write for the sink of the application, when defined, and for the sink of each Server
+//
+// Português:
+//
+//  Chama write para o coletor da aplicação, quando definido, e para o coletor de cada Server
+func (e *prometheusFanoutSink) each(write func(sink metrics.MetricSink)) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if e.application != nil {
+		write(e.application)
+	}
+
+	for sink := range e.sinks {
+		write(sink)
+	}
+}
+
+// add
+//
+// English:
+//
+//  Starts copying the metrics of memberlist to the sink of a Server
+//
+// Português:
+//
+//  Começa a copiar as métricas da memberlist para o coletor de um Server
+func (e *prometheusFanoutSink) add(sink *prometheusSink) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.sinks[sink] = struct{}{}
+}
+
+// remove
+//
+// English:
+//
+//  Stops copying the metrics of memberlist to the sink of a Server
+//
+// Português:
+//
+//  Para de copiar as métricas da memberlist para o coletor de um Server
+func (e *prometheusFanoutSink) remove(sink *prometheusSink) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	delete(e.sinks, sink)
+}
+
+var (
+	// memberlistMetrics
+	//
+	// English: go-metrics keeps a single global instance, shared by the application and by all Server
+	// of the process, so it is only replaced by EnableMemberlistMetrics()
+	//
+	// Português: o go-metrics mantém uma única instância global, compartilhada pela aplicação e por
+	// todos os Server do processo, por isto ela só é trocada por EnableMemberlistMetrics()
+	memberlistMetrics     = &prometheusFanoutSink{sinks: make(map[*prometheusSink]struct{})}
+	memberlistMetricsOnce sync.Once
+)
+
+// EnableMemberlistMetrics
+//
+// English:
+//
+//  Adds the gossip, probe and message metrics of memberlist, memberlist_*, to the metrics of all
+//  Server of the process.
+//
+//  memberlist only writes to the global go-metrics instance, so this function replaces it by a sink
+//  that copies each metric to the sink of the application and to the sinks of the Server.
+//
+//   Input:
+//     applicationSink: sink used by the application until now, which keeps receiving all global
+//                      metrics. nil when the application doesn't use go-metrics
+//
+//   Note:
+//     * Without this function, the global go-metrics instance is never changed by this package;
+//     * The memberlist metrics don't have the node name, in a process with several Server all of
+//       them receive the metrics of all memberlist instances.
+//
+// Português:
+//
+//  Adiciona as métricas de fofoca, de sondagem e de mensagens da memberlist, memberlist_*, às
+//  métricas de todos os Server do processo.
+//
+//  A memberlist só escreve na instância global do go-metrics, por isto esta função a troca por um
+//  coletor que copia cada métrica para o coletor da aplicação e para os coletores dos Server.
+//
+//   Entrada:
+//     applicationSink: coletor usado pela aplicação até agora, que continua recebendo todas as
+//                      métricas globais. nil quando a aplicação não usa o go-metrics
+//
+//   Nota:
+//     * Sem esta função, a instância global do go-metrics nunca é alterada por este pacote;
+//     * As métricas da memberlist não têm o nome do node, em um processo com vários Server todos
+//       recebem as métricas de todas as instâncias da memberlist.
+func EnableMemberlistMetrics(applicationSink metrics.MetricSink) {
+	memberlistMetrics.mutex.Lock()
+	memberlistMetrics.application = applicationSink
+	memberlistMetrics.mutex.Unlock()
+
+	memberlistMetricsOnce.Do(func() {
+		_, _ = metrics.NewGlobal(newMetricsConfig(), memberlistMetrics)
+	})
+}
+
+// newMetricsConfig
+//
+// English:
+//
+//  Returns the go-metrics configuration of the package, without the host name and the runtime
+//  metrics, with the timings in milliseconds
+//
+// Português:
+//
+//  Retorna a configuração do go-metrics do pacote, sem o nome do host e as métricas de runtime, com
+//  os tempos em milissegundos
+func newMetricsConfig() (conf *metrics.Config) {
+	conf = metrics.DefaultConfig("")
+	conf.EnableHostname = false
+	conf.EnableRuntimeMetrics = false
+	conf.TimerGranularity = time.Millisecond
+	return
+}
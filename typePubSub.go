@@ -0,0 +1,439 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	//kPubSubPendingLimit
+	//
+	// English:
+	//
+	// Maximum number of messages kept for a subscriber until they are acknowledged. When the limit is
+	// reached, the oldest message is discarded.
+	//
+	// Português:
+	//
+	// Quantidade máxima de mensagens mantidas para um inscrito até serem confirmadas. Quando o limite é
+	// atingido, a mensagem mais antiga é descartada.
+	kPubSubPendingLimit = 1024
+)
+
+// pubSubPublisher
+//
+// English:
+//
+//  Identifies a publisher. The incarnation changes when the process restarts, and with it the
+//  sequence of the messages starts again
+//
+// Português:
+//
+//  Identifica um publicador. A encarnação muda quando o processo reinicia, e com ela a sequência das
+//  mensagens começa novamente
+type pubSubPublisher struct {
+	From        string
+	Incarnation string
+}
+
+// pubSubSession
+//
+// English:
+//
+//  Stream opened by a subscriber to this node
+//
+//   Fields:
+//     wake: signals that there are new messages to send
+//     done: closed when the session is replaced by a new stream of the same subscriber or when the
+//           server stops
+//
+// Português:
+//
+//  Stream aberto por um inscrito para este node
+//
+//   Campos:
+//     wake: sinaliza que existem novas mensagens para enviar
+//     done: fechado quando a sessão é substituída por um novo stream do mesmo inscrito ou quando o
+//           servidor para
+type pubSubSession struct {
+	wake chan struct{}
+	done chan struct{}
+}
+
+// pubSubSubscriber
+//
+// English:
+//
+//  Member subscribed to topics of this node
+//
+//   Fields:
+//     topics: topics of the subscriber
+//     pending: messages not acknowledged, ordered by sequence
+//     session: current stream of the subscriber, nil when disconnected
+//
+// Português:
+//
+//  Membro inscrito em tópicos deste node
+//
+//   Campos:
+//     topics: tópicos do inscrito
+//     pending: mensagens não confirmadas, ordenadas pela sequência
+//     session: stream atual do inscrito, nil quando desconectado
+type pubSubSubscriber struct {
+	topics  map[string]struct{}
+	pending []*grpcProto.PubSubMessage
+	session *pubSubSession
+}
+
+// pubSub
+//
+// English:
+//
+//  State of the publish/subscribe between the members. As publisher, keeps the subscribers of this
+//  node and their pending messages. As subscriber, keeps the topic handlers, the streams opened to
+//  the other members and the last sequence delivered of each publisher
+//
+// Português:
+//
+//  Estado da publicação/inscrição entre os membros. Como publicador, mantém os inscritos deste node
+//  e as suas mensagens pendentes. Como inscrito, mantém as funções dos tópicos, os streams abertos
+//  para os demais membros e a última sequência entregue de cada publicador
+type pubSub struct {
+	mutex       sync.Mutex
+	stopped     bool
+	incarnation string
+	sequence    uint64
+	subscribers map[string]*pubSubSubscriber
+	handlers    map[string]TopicHandler
+	streams     map[string]*pubSubStream
+	delivered   map[pubSubPublisher]uint64
+}
+
+// newPubSub
+//
+// English:
+//
+//  Returns the state without subscribers and without topics
+//
+// Português:
+//
+//  Retorna o estado sem inscritos e sem tópicos
+func newPubSub() (state *pubSub) {
+	return &pubSub{
+		incarnation: strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: make(map[string]*pubSubSubscriber),
+		handlers:    make(map[string]TopicHandler),
+		streams:     make(map[string]*pubSubStream),
+		delivered:   make(map[pubSubPublisher]uint64),
+	}
+}
+
+// publish
+//
+// English:
+//
+//  Queues the message for all subscribers of the topic
+//
+//   Output:
+//     subscribers: number of subscribers of the topic
+//     discarded: subscribers that lost their oldest pending message because of kPubSubPendingLimit
+//
+// Português:
+//
+//  Coloca a mensagem na fila de todos os inscritos do tópico
+//
+//   Saída:
+//     subscribers: quantidade de inscritos do tópico
+//     discarded: inscritos que perderam a sua mensagem pendente mais antiga por causa de
+//                kPubSubPendingLimit
+func (e *pubSub) publish(from, topic string, payload []byte) (subscribers int, discarded []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.sequence += 1
+	var message = &grpcProto.PubSubMessage{
+		From:        from,
+		Incarnation: e.incarnation,
+		Sequence:    e.sequence,
+		Topic:       topic,
+		Payload:     append([]byte{}, payload...),
+	}
+
+	for name, subscriber := range e.subscribers {
+		if _, found := subscriber.topics[topic]; found == false {
+			continue
+		}
+
+		subscribers += 1
+		subscriber.pending = append(subscriber.pending, message)
+		if len(subscriber.pending) > kPubSubPendingLimit {
+			subscriber.pending = subscriber.pending[1:]
+			discarded = append(discarded, name)
+		}
+
+		if subscriber.session != nil {
+			select {
+			case subscriber.session.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	return
+}
+
+// open
+//
+// English:
+//
+//  Starts the session of a subscriber, replacing its previous session. The pending messages are
+//  sent again from the oldest
+//
+//   Output:
+//     session: the new session, nil when the server is stopped
+//
+// Português:
+//
+//  Inicia a sessão de um inscrito, substituindo a sua sessão anterior. As mensagens pendentes são
+//  enviadas novamente a partir da mais antiga
+//
+//   Saída:
+//     session: a nova sessão, nil quando o servidor está parado
+func (e *pubSub) open(node string, topics []string) (session *pubSubSession) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stopped == true {
+		return
+	}
+
+	var subscriber = e.subscribers[node]
+	if subscriber == nil {
+		subscriber = &pubSubSubscriber{}
+		e.subscribers[node] = subscriber
+	}
+
+	if subscriber.session != nil {
+		close(subscriber.session.done)
+	}
+
+	session = &pubSubSession{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	session.wake <- struct{}{}
+	subscriber.session = session
+	subscriber.topics = topicSet(topics)
+	return
+}
+
+// close
+//
+// English:
+//
+//  Ends the session of a subscriber, keeping its pending messages for the next session
+//
+// Português:
+//
+//  Termina a sessão de um inscrito, mantendo as suas mensagens pendentes para a próxima sessão
+func (e *pubSub) close(node string, session *pubSubSession) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var subscriber = e.subscribers[node]
+	if subscriber == nil || subscriber.session != session {
+		return
+	}
+
+	close(session.done)
+	subscriber.session = nil
+}
+
+// subscribe
+//
+// English:
+//
+//  Replaces the topics of a subscriber
+//
+// Português:
+//
+//  Substitui os tópicos de um inscrito
+func (e *pubSub) subscribe(node string, topics []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if subscriber := e.subscribers[node]; subscriber != nil {
+		subscriber.topics = topicSet(topics)
+	}
+}
+
+// ack
+//
+// English:
+//
+//  Removes the pending messages of the subscriber up to the sequence
+//
+// Português:
+//
+//  Remove as mensagens pendentes do inscrito até a sequência
+func (e *pubSub) ack(node string, sequence uint64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var subscriber = e.subscribers[node]
+	if subscriber == nil {
+		return
+	}
+
+	var index = 0
+	for index < len(subscriber.pending) && subscriber.pending[index].Sequence <= sequence {
+		index += 1
+	}
+	subscriber.pending = subscriber.pending[index:]
+}
+
+// next
+//
+// English:
+//
+//  Returns the pending messages of the subscriber after the sequence
+//
+// Português:
+//
+//  Retorna as mensagens pendentes do inscrito depois da sequência
+func (e *pubSub) next(node string, sequence uint64) (messages []*grpcProto.PubSubMessage) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var subscriber = e.subscribers[node]
+	if subscriber == nil {
+		return
+	}
+
+	for _, message := range subscriber.pending {
+		if message.Sequence > sequence {
+			messages = append(messages, message)
+		}
+	}
+
+	return
+}
+
+// deliver
+//
+// English:
+//
+//  Returns the handler of the topic of a received message, or nil when the message was already
+//  delivered or nobody handles the topic
+//
+// Português:
+//
+//  Retorna a função do tópico de uma mensagem recebida, ou nil quando a mensagem já foi entregue ou
+//  ninguém trata o tópico
+func (e *pubSub) deliver(message *grpcProto.PubSubMessage) (handler TopicHandler) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var publisher = pubSubPublisher{From: message.GetFrom(), Incarnation: message.GetIncarnation()}
+	if message.GetSequence() <= e.delivered[publisher] {
+		return
+	}
+
+	e.delivered[publisher] = message.GetSequence()
+	return e.handlers[message.GetTopic()]
+}
+
+// topics
+//
+// English:
+//
+//  Returns the topics handled by this node, in order
+//
+// Português:
+//
+//  Retorna os tópicos tratados por este node, em ordem
+func (e *pubSub) topics() (topics []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	topics = make([]string, 0, len(e.handlers))
+	for topic := range e.handlers {
+		topics = append(topics, topic)
+	}
+
+	sort.Strings(topics)
+	return
+}
+
+// prune
+//
+// English:
+//
+//  Forgets the disconnected subscribers and the publishers that are no longer members
+//
+// Português:
+//
+//  Esquece os inscritos desconectados e os publicadores que não são mais membros
+func (e *pubSub) prune(members map[string]bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for name, subscriber := range e.subscribers {
+		if subscriber.session == nil && members[name] == false {
+			delete(e.subscribers, name)
+		}
+	}
+
+	for publisher := range e.delivered {
+		if members[publisher.From] == false {
+			delete(e.delivered, publisher)
+		}
+	}
+}
+
+// stop
+//
+// English:
+//
+//  Ends all sessions and streams and refuses new sessions
+//
+// Português:
+//
+//  Termina todas as sessões e streams e recusa novas sessões
+func (e *pubSub) stop() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.stopped = true
+	for _, subscriber := range e.subscribers {
+		if subscriber.session != nil {
+			close(subscriber.session.done)
+			subscriber.session = nil
+		}
+	}
+
+	for _, stream := range e.streams {
+		stream.cancel()
+	}
+}
+
+// topicSet
+//
+// English:
+//
+//  Converts the list of topics into a set
+//
+// Português:
+//
+//  Converte a lista de tópicos em um conjunto
+func topicSet(topics []string) (set map[string]struct{}) {
+	set = make(map[string]struct{}, len(topics))
+	for _, topic := range topics {
+		set[topic] = struct{}{}
+	}
+
+	return
+}
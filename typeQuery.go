@@ -0,0 +1,350 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QueryParams
+//
+// English:
+//
+//  Options of Server.Query()
+//
+//   Fields:
+//     Selector: tag selector of the nodes that receive the query, see ParseTagSelector(). Empty
+//               sends the query to all members, including this node
+//     OnlyReady: true to send the query only to the ready members
+//     Timeout: maximum time to wait for the answers, by default, kQueryTimeout
+//
+// Português:
+//
+//  Opções de Server.Query()
+//
+//   Campos:
+//     Selector: seletor de tags dos nodes que recebem a consulta, veja ParseTagSelector(). Vazio
+//               envia a consulta para todos os membros, incluindo este node
+//     OnlyReady: true para enviar a consulta apenas para os membros prontos
+//     Timeout: tempo máximo de espera pelas respostas, por padrão, kQueryTimeout
+type QueryParams struct {
+	Selector  string
+	OnlyReady bool
+	Timeout   time.Duration
+}
+
+// QueryResponse
+//
+// English:
+//
+//  Answer of a node to a query
+//
+//   Fields:
+//     From: name of the node
+//     Payload: answer of the handler
+//     Err: error of the node, with the same types returned by Server.Call()
+//
+// Português:
+//
+//  Resposta de um node a uma consulta
+//
+//   Campos:
+//     From: nome do node
+//     Payload: resposta da função
+//     Err: erro do node, com os mesmos tipos retornados por Server.Call()
+type QueryResponse struct {
+	From    string
+	Payload []byte
+	Err     error
+}
+
+// Query
+//
+// English:
+//
+//  Query in progress, returned by Server.Query()
+//
+//  Each node acknowledges the query when it receives it and answers when its handler returns. The
+//  query finishes when all targets answered, when the timeout expires or when Close() is called,
+//  and then the channels are closed
+//
+// Português:
+//
+//  Consulta em andamento, retornada por Server.Query()
+//
+//  Cada node confirma a consulta quando a recebe e responde quando a sua função retorna. A consulta
+//  termina quando todos os alvos responderam, quando o tempo limite expira ou quando Close() é
+//  chamado, e então os canais são fechados
+type Query struct {
+	name          string
+	targets       []string
+	deadline      time.Time
+	cancel        context.CancelFunc
+	mutex         sync.Mutex
+	finished      bool
+	waiting       int
+	acked         map[string]bool
+	answered      map[string]bool
+	ackCount      int
+	responseCount int
+	acks          chan string
+	responses     chan QueryResponse
+	done          chan struct{}
+}
+
+// newQuery
+//
+// English:
+//
+//  Returns a query waiting for the answers of the targets. The channels have room for one value
+//  per target and only the first acknowledgement and the first answer of each target are accepted,
+//  so the query never waits for the reader
+//
+// Português:
+//
+//  Retorna uma consulta esperando as respostas dos alvos. Os canais têm espaço para um valor por
+//  alvo e apenas a primeira confirmação e a primeira resposta de cada alvo são aceitas, assim a
+//  consulta nunca espera pelo leitor
+func newQuery(name string, targets []string, deadline time.Time, cancel context.CancelFunc) (query *Query) {
+	query = &Query{
+		name:      name,
+		targets:   targets,
+		deadline:  deadline,
+		cancel:    cancel,
+		waiting:   len(targets),
+		acks:      make(chan string, len(targets)),
+		responses: make(chan QueryResponse, len(targets)),
+		acked:     make(map[string]bool, len(targets)),
+		answered:  make(map[string]bool, len(targets)),
+		done:      make(chan struct{}),
+	}
+
+	for _, target := range targets {
+		query.acked[target] = false
+		query.answered[target] = false
+	}
+
+	if len(targets) == 0 {
+		query.finish()
+	}
+
+	return
+}
+
+// Name
+//
+// English:
+//
+//  Returns the name of the query
+//
+// Português:
+//
+//  Retorna o nome da consulta
+func (e *Query) Name() (name string) {
+	return e.name
+}
+
+// Targets
+//
+// English:
+//
+//  Returns the names of the nodes that received the query
+//
+// Português:
+//
+//  Retorna os nomes dos nodes que receberam a consulta
+func (e *Query) Targets() (targets []string) {
+	return append([]string{}, e.targets...)
+}
+
+// Deadline
+//
+// English:
+//
+//  Returns the time at which the query finishes if some target didn't answer
+//
+// Português:
+//
+//  Retorna o momento em que a consulta termina se algum alvo não respondeu
+func (e *Query) Deadline() (deadline time.Time) {
+	return e.deadline
+}
+
+// Acks
+//
+// English:
+//
+//  Returns the channel that receives the name of each node that acknowledged the query. Closed when
+//  the query finishes
+//
+// Português:
+//
+//  Retorna o canal que recebe o nome de cada node que confirmou a consulta. Fechado quando a
+//  consulta termina
+func (e *Query) Acks() (acks <-chan string) {
+	return e.acks
+}
+
+// Responses
+//
+// English:
+//
+//  Returns the channel that receives the answer of each node. Closed when the query finishes
+//
+// Português:
+//
+//  Retorna o canal que recebe a resposta de cada node. Fechado quando a consulta termina
+func (e *Query) Responses() (responses <-chan QueryResponse) {
+	return e.responses
+}
+
+// Done
+//
+// English:
+//
+//  Returns a channel closed when the query finishes
+//
+// Português:
+//
+//  Retorna um canal fechado quando a consulta termina
+func (e *Query) Done() (done <-chan struct{}) {
+	return e.done
+}
+
+// Finished
+//
+// English:
+//
+//  Returns true if the query finished
+//
+// Português:
+//
+//  Retorna true se a consulta terminou
+func (e *Query) Finished() (finished bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.finished
+}
+
+// AckCount
+//
+// English:
+//
+//  Returns the number of nodes that acknowledged the query
+//
+// Português:
+//
+//  Retorna a quantidade de nodes que confirmaram a consulta
+func (e *Query) AckCount() (count int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.ackCount
+}
+
+// ResponseCount
+//
+// English:
+//
+//  Returns the number of nodes that answered the query, including the answers with error
+//
+// Português:
+//
+//  Retorna a quantidade de nodes que responderam a consulta, incluindo as respostas com erro
+func (e *Query) ResponseCount() (count int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.responseCount
+}
+
+// Close
+//
+// English:
+//
+//  Finishes the query before the timeout, the answers not received are discarded
+//
+// Português:
+//
+//  Termina a consulta antes do tempo limite, as respostas não recebidas são descartadas
+func (e *Query) Close() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.finish()
+}
+
+// ack
+//
+// English:
+//
+//  Records the first acknowledgement of a target. Repeated acknowledgements and nodes that are not
+//  targets are ignored
+//
+// Português:
+//
+//  Registra a primeira confirmação de um alvo. Confirmações repetidas e nodes que não são alvos são
+//  ignorados
+func (e *Query) ack(node string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if acked, target := e.acked[node]; e.finished == true || target == false || acked == true {
+		return
+	}
+
+	e.acked[node] = true
+	e.ackCount += 1
+	e.acks <- node
+}
+
+// respond
+//
+// English:
+//
+//  Records the first answer of a target and finishes the query after the last one. Repeated answers
+//  and nodes that are not targets are ignored
+//
+// Português:
+//
+//  Registra a primeira resposta de um alvo e termina a consulta depois da última. Respostas
+//  repetidas e nodes que não são alvos são ignorados
+func (e *Query) respond(response QueryResponse) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if answered, target := e.answered[response.From]; e.finished == true || target == false || answered == true {
+		return
+	}
+
+	e.answered[response.From] = true
+	e.responseCount += 1
+	e.responses <- response
+
+	e.waiting -= 1
+	if e.waiting == 0 {
+		e.finish()
+	}
+}
+
+// finish
+//
+// English:
+//
+//  Closes the channels and cancels the calls in progress. Must be called with the mutex locked
+//
+// Português:
+//
+//  Fecha os canais e cancela as chamadas em andamento. Deve ser chamado com o mutex travado
+func (e *Query) finish() {
+	if e.finished == true {
+		return
+	}
+
+	e.finished = true
+	close(e.acks)
+	close(e.responses)
+	close(e.done)
+	e.cancel()
+}
@@ -0,0 +1,75 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryAcksAndResponses(t *testing.T) {
+	var tests = []struct {
+		name          string
+		acks          []string
+		responses     []string
+		wantAcks      int
+		wantResponses int
+		wantFinished  bool
+	}{
+		{name: "all targets", acks: []string{"a", "b"}, responses: []string{"a", "b"}, wantAcks: 2, wantResponses: 2, wantFinished: true},
+		{name: "repeated ack", acks: []string{"a", "a", "a", "b", "b"}, responses: []string{"a"}, wantAcks: 2, wantResponses: 1},
+		{name: "repeated response", acks: []string{"a"}, responses: []string{"a", "a", "a"}, wantAcks: 1, wantResponses: 1},
+		{name: "not a target", acks: []string{"c", "a"}, responses: []string{"c", "a"}, wantAcks: 1, wantResponses: 1},
+		{name: "after the end", acks: []string{"a", "b"}, responses: []string{"a", "b", "a"}, wantAcks: 2, wantResponses: 2, wantFinished: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cancelled = false
+			var query = newQuery("q", []string{"a", "b"}, time.Now(), func() { cancelled = true })
+
+			// English: the channels are not read, a blocking send would hang the test
+			// Português: os canais não são lidos, um envio bloqueante travaria o teste
+			for _, node := range test.acks {
+				query.ack(node)
+			}
+			for _, node := range test.responses {
+				query.respond(QueryResponse{From: node})
+			}
+
+			if query.AckCount() != test.wantAcks || query.ResponseCount() != test.wantResponses {
+				t.Fatalf("counts = %d, %d, want %d, %d", query.AckCount(), query.ResponseCount(), test.wantAcks, test.wantResponses)
+			}
+
+			if query.Finished() != test.wantFinished || cancelled != test.wantFinished {
+				t.Fatalf("finished = %v, cancelled = %v, want %v", query.Finished(), cancelled, test.wantFinished)
+			}
+
+			query.Close()
+
+			var responses = 0
+			for range query.Responses() {
+				responses += 1
+			}
+			if responses != test.wantResponses {
+				t.Fatalf("%d responses in the channel, want %d", responses, test.wantResponses)
+			}
+		})
+	}
+}
+
+func TestQueryWithoutTargets(t *testing.T) {
+	var query = newQuery("q", nil, time.Now(), func() {})
+
+	select {
+	case <-query.Done():
+	default:
+		t.Fatal("a query without targets is not finished")
+	}
+
+	query.respond(QueryResponse{From: "a", Err: errors.New("late")})
+	query.Close()
+
+	if query.ResponseCount() != 0 {
+		t.Fatalf("ResponseCount() = %d, want 0", query.ResponseCount())
+	}
+}
@@ -0,0 +1,289 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	//kDnsLookupTimeout
+	//
+	// English:
+	//
+	// Default maximum time of a DNS lookup.
+	//
+	// Português:
+	//
+	// Tempo máximo padrão de uma consulta DNS.
+	kDnsLookupTimeout = 2 * time.Second
+)
+
+// Resolver
+//
+// English:
+//
+//  Resolves the names of the services/containers into IP addresses and the SRV records of the
+//  services.
+//
+//   Note:
+//     * Use Server.SetResolver() to replace the default resolver;
+//     * NewDnsResolver() returns the default implementation and clustertest.FakeResolver a
+//       scriptable one, for tests;
+//     * The implementations must be safe for concurrent use.
+//
+// Português:
+//
+//  Resolve os nomes dos serviços/containers em endereços IP e os registros SRV dos serviços.
+//
+//   Nota:
+//     * Use Server.SetResolver() para trocar o resolvedor padrão;
+//     * NewDnsResolver() retorna a implementação padrão e clustertest.FakeResolver uma programável,
+//       para testes;
+//     * As implementações devem ser seguras para uso concorrente.
+type Resolver interface {
+	LookupIP(ctx context.Context, host string) (addresses []net.IP, err error)
+	LookupSRV(ctx context.Context, service, proto, name string) (records []*net.SRV, err error)
+}
+
+// srvName
+//
+// English:
+//
+//  Returns the name looked up for the SRV records, _service._proto.name or, when service and proto
+//  are empty, name, the same rule of net.LookupSRV()
+//
+// Português:
+//
+//  Retorna o nome consultado para os registros SRV, _service._proto.name ou, quando service e proto
+//  são vazios, name, a mesma regra de net.LookupSRV()
+func srvName(service, proto, name string) string {
+	if service == "" && proto == "" {
+		return name
+	}
+
+	return "_" + service + "._" + proto + "." + name
+}
+
+// copySRV
+//
+// English:
+//
+//  Returns a copy of the SRV records, so the caller can't change the records kept in a cache
+//
+// Português:
+//
+//  Retorna uma cópia dos registros SRV, assim quem chamou não pode alterar os registros guardados em
+//  um cache
+func copySRV(records []*net.SRV) (copied []*net.SRV) {
+	copied = make([]*net.SRV, 0, len(records))
+	for _, record := range records {
+		var value = *record
+		copied = append(copied, &value)
+	}
+
+	return
+}
+
+// dnsCacheEntry
+//
+// English:
+//
+//  Answer kept in the cache of DnsResolver
+//
+// Português:
+//
+//  Resposta guardada no cache do DnsResolver
+type dnsCacheEntry struct {
+	addresses []net.IP
+	records   []*net.SRV
+	expires   time.Time
+}
+
+// DnsResolver
+//
+// English:
+//
+//  Resolver based on net.Resolver, with an optional name server, a timeout for each lookup and a
+//  cache of the answers
+//
+// Português:
+//
+//  Resolvedor baseado em net.Resolver, com um servidor de nomes opcional, um tempo limite para cada
+//  consulta e um cache das respostas
+type DnsResolver struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+	cacheTTL time.Duration
+	mutex    sync.Mutex
+	cache    map[string]dnsCacheEntry
+}
+
+// NewDnsResolver
+//
+// English:
+//
+//  Returns a resolver based on net.Resolver
+//
+//   Input:
+//     nameserver: address of the name server in the host:port format, for example "10.0.0.2:53".
+//                 Empty uses the name servers of the system
+//     timeout: maximum time of each lookup. Zero uses 2 seconds
+//     cacheTTL: time the answers are kept in the cache. Zero disables the cache
+//
+//   Note:
+//     * Only the successful answers are kept in the cache.
+//
+// Português:
+//
+//  Retorna um resolvedor baseado em net.Resolver
+//
+//   Entrada:
+//     nameserver: endereço do servidor de nomes no formato host:porta, por exemplo "10.0.0.2:53".
+//                 Vazio usa os servidores de nomes do sistema
+//     timeout: tempo máximo de cada consulta. Zero usa 2 segundos
+//     cacheTTL: tempo em que as respostas são mantidas no cache. Zero desabilita o cache
+//
+//   Nota:
+//     * Apenas as respostas de sucesso são mantidas no cache.
+func NewDnsResolver(nameserver string, timeout, cacheTTL time.Duration) (resolver *DnsResolver) {
+	if timeout <= 0 {
+		timeout = kDnsLookupTimeout
+	}
+
+	resolver = &DnsResolver{
+		resolver: &net.Resolver{},
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]dnsCacheEntry),
+	}
+
+	if nameserver != "" {
+		resolver.resolver.PreferGo = true
+		resolver.resolver.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, nameserver)
+		}
+	}
+
+	return
+}
+
+// LookupIP
+//
+// English:
+//
+//  Returns the IP addresses of the host, from the cache when the answer has not expired
+//
+//   Input:
+//     ctx: cancels the lookup, limited by the timeout of the resolver
+//     host: name of the service/container
+//
+//   Output:
+//     addresses: IP addresses of the host
+//     err: *net.DNSError, IsNotFound for NXDOMAIN and IsTimeout for a timeout
+//
+// Português:
+//
+//  Retorna os endereços IP do host, do cache quando a resposta não expirou
+//
+//   Entrada:
+//     ctx: cancela a consulta, limitado pelo tempo limite do resolvedor
+//     host: nome do serviço/container
+//
+//   Saída:
+//     addresses: endereços IP do host
+//     err: *net.DNSError, IsNotFound para NXDOMAIN e IsTimeout para um tempo limite
+func (e *DnsResolver) LookupIP(ctx context.Context, host string) (addresses []net.IP, err error) {
+	var now = time.Now()
+
+	if e.cacheTTL > 0 {
+		e.mutex.Lock()
+		var entry, found = e.cache[host]
+		e.mutex.Unlock()
+
+		if found == true && now.Before(entry.expires) {
+			addresses = append([]net.IP{}, entry.addresses...)
+			return
+		}
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	addresses, err = e.resolver.LookupIP(ctx, "ip", host)
+	if err != nil || e.cacheTTL <= 0 {
+		return
+	}
+
+	e.mutex.Lock()
+	e.cache[host] = dnsCacheEntry{addresses: append([]net.IP{}, addresses...), expires: now.Add(e.cacheTTL)}
+	e.mutex.Unlock()
+
+	return
+}
+
+// LookupSRV
+//
+// English:
+//
+//  Returns the SRV records of the service, from the cache when the answer has not expired
+//
+//   Input:
+//     ctx: cancels the lookup, limited by the timeout of the resolver
+//     service: name of the service, for example, "memberlist". Empty to look up name directly
+//     proto: protocol, for example, "tcp". Empty to look up name directly
+//     name: domain name
+//
+//   Output:
+//     records: SRV records, sorted by priority and randomized by weight
+//     err: *net.DNSError, IsNotFound for NXDOMAIN and IsTimeout for a timeout
+//
+// Português:
+//
+//  Retorna os registros SRV do serviço, do cache quando a resposta não expirou
+//
+//   Entrada:
+//     ctx: cancela a consulta, limitado pelo tempo limite do resolvedor
+//     service: nome do serviço, por exemplo, "memberlist". Vazio para consultar name diretamente
+//     proto: protocolo, por exemplo, "tcp". Vazio para consultar name diretamente
+//     name: nome de domínio
+//
+//   Saída:
+//     records: registros SRV, ordenados pela prioridade e embaralhados pelo peso
+//     err: *net.DNSError, IsNotFound para NXDOMAIN e IsTimeout para um tempo limite
+func (e *DnsResolver) LookupSRV(ctx context.Context, service, proto, name string) (records []*net.SRV, err error) {
+	var now = time.Now()
+
+	// English: the prefix keeps the SRV answers apart from the IP answers of the same name
+	// Português: o prefixo separa as respostas SRV das respostas IP do mesmo nome
+	var key = "srv " + srvName(service, proto, name)
+
+	if e.cacheTTL > 0 {
+		e.mutex.Lock()
+		var entry, found = e.cache[key]
+		e.mutex.Unlock()
+
+		if found == true && now.Before(entry.expires) {
+			records = copySRV(entry.records)
+			return
+		}
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	_, records, err = e.resolver.LookupSRV(ctx, service, proto, name)
+	if err != nil || e.cacheTTL <= 0 {
+		return
+	}
+
+	e.mutex.Lock()
+	e.cache[key] = dnsCacheEntry{records: copySRV(records), expires: now.Add(e.cacheTTL)}
+	e.mutex.Unlock()
+
+	return
+}
@@ -1,13 +1,11 @@
 package iotmaker_docker_builder_demo
 
 import (
-	"github.com/hashicorp/logutils"
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/memberlist"
-	"github.com/helmutkemper/util"
-	"log"
+	"google.golang.org/grpc"
 	"net"
-	"os"
-	"strings"
+	"net/http"
 	"sync"
 	"time"
 )
@@ -31,12 +29,90 @@ const (
 
 type Server struct {
 	thisInstanceIsReady        bool
+	thisInstanceIsLive         bool
+	failedChecks               []string
+	healthMutex                sync.Mutex
+	readinessChecks            []healthCheck
+	livenessChecks             []healthCheck
 	thisNodeAddress            string
 	syncPort                   int
+	bindAddress                string
+	advertiseAddress           string
+	advertisePort              int
+	nodeName                   string
+	networkProfile             NetworkProfile
+	gossipInterval             time.Duration
+	probeInterval              time.Duration
+	probeTimeout               time.Duration
+	dnsCheckInterval           time.Duration
 	serviceNameList            []string
+	discoveryList              []Discovery
 	memberList                 *memberlist.Memberlist
 	syncBetweenInstancesTicker *time.Ticker
-	nodeNamesList              *sync.Map
+	syncBetweenInstancesStop   chan struct{}
+	syncBetweenInstancesDone   chan struct{}
+	shutdownOnce               sync.Once
+	nodeHistory                *nodeHistory
+	nodeHistoryPath            string
+	nodeReapTTL                time.Duration
+	nodeIDPath                 string
+	nodeIDEnv                  string
+	nodeEventMutex             sync.Mutex
+	nodeEventSubscribers       map[chan NodeEvent]struct{}
+	nodeMetaMutex              sync.Mutex
+	nodeMeta                   nodeMeta
+	keyValueOnce               sync.Once
+	keyValue                   *keyValueStore
+	counterOnce                sync.Once
+	counters                   *counterStore
+	pubSubOnce                 sync.Once
+	pubSub                     *pubSub
+	userEventOnce              sync.Once
+	userEvents                 *userEvents
+	userEventHandler           UserEventHandler
+	userEventWindow            time.Duration
+	broadcastQueue             *memberlist.TransmitLimitedQueue
+	stateMutex                 sync.RWMutex
+	grpcPort                   int
+	grpcListener               net.Listener
+	grpcServer                 *grpc.Server
+	grpcMutex                  sync.Mutex
+	grpcConnections            map[string]*grpc.ClientConn
+	communicationHandler       CommunicationHandler
+	callHandlers               map[string]CommunicationHandler
+	queryHandlers              map[string]QueryHandler
+	leaderMutex                sync.Mutex
+	leaderName                 string
+	leaderCandidate            string
+	leaderCandidateSince       time.Time
+	leaderGracePeriod          time.Duration
+	leaderChangeHandler        LeaderChangeHandler
+	ringMutex                  sync.RWMutex
+	ring                       *hashRing
+	ringNodes                  map[string]int
+	ringVirtualNodes           int
+	ringSubscriberMutex        sync.Mutex
+	ringSubscribers            map[chan RingChange]struct{}
+	localName                  string
+	membersMutex               sync.RWMutex
+	members                    map[string]Member
+	keyring                    *memberlist.Keyring
+	keyringMutex               sync.Mutex
+	keyringPending             map[string]chan keyringResponse
+	expectedMembers            int
+	joinDiscovered             int
+	joinFailures               int
+	joinNextAttempt            time.Time
+	metricsAddress             string
+	metricsListener            net.Listener
+	metricsServer              *http.Server
+	metricsOnce                sync.Once
+	metricsSink                *prometheusSink
+	metricsWriter              *metrics.Metrics
+	logger                     Logger
+	resolverOnce               sync.Once
+	resolver                   Resolver
+	addressFamily              AddressFamily
 }
 
 // AddServersByName
@@ -53,38 +129,64 @@ func (e *Server) AddServersByName(servers ...string) {
 	e.serviceNameList = append(e.serviceNameList, servers...)
 }
 
+// AddDiscovery
+//
+// English:
+//
+//  Adds providers used to find the other instances, besides the names added by AddServersByName()
+//
+//   Input:
+//     providers: DiscoveryDns, DiscoveryDnsSrv, DiscoveryStatic, DiscoveryFile, DiscoveryEnv or any
+//                other implementation of the Discovery interface
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Adiciona provedores usados para encontrar as demais instâncias, além dos nomes adicionados por
+//  AddServersByName()
+//
+//   Entrada:
+//     providers: DiscoveryDns, DiscoveryDnsSrv, DiscoveryStatic, DiscoveryFile, DiscoveryEnv ou
+//                qualquer outra implementação da interface Discovery
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) AddDiscovery(providers ...Discovery) {
+	e.discoveryList = append(e.discoveryList, providers...)
+}
+
 // ipAddressClear
 //
 // English:
 //
-//  Returns only the IPV4 address
+//  Returns only the IP address, without the port
 //
-//  The IP address is given in X.X.X.X.X:PORT format and this function only returns the X.X.X.X address
+//  The address is given in the X.X.X.X:PORT or [X:X::X]:PORT format and this function returns the
+//  X.X.X.X or X:X::X address
 //
 //   Output:
-//     address: IPV4 address in X.X.X.X format
+//     IP: IPv4 or IPv6 address, without brackets
 //
 // Português:
 //
-//  Retorna apenas o endereço IPV4
+//  Retorna apenas o endereço IP, sem a porta
 //
-//  O endereço IP é dado no formato X.X.X.X:PORT e esta função retorna apenas o endereço X.X.X.X
+//  O endereço é dado no formato X.X.X.X:PORT ou [X:X::X]:PORT e esta função retorna o endereço
+//  X.X.X.X ou X:X::X
 //
 //   Saída:
-//     address: endereço IPV4 no formato X.X.X.X
+//     IP: endereço IPv4 ou IPv6, sem colchetes
 func (e *Server) ipAddressClear(address string) (IP string) {
-	if strings.Split(address, ":")[0] == "" {
-		return
-	}
-	IP = strings.Split(address, ":")[0]
-	return
+	return hostOf(address)
 }
 
 // getAndUpdateThisInstanceAddress
 //
 // English:
 //
-//  Updates the current address of the container
+//  Updates the current address of the container and runs the health checks
 //
 //  The server address may change when the container is restarted
 //
@@ -94,7 +196,7 @@ func (e *Server) ipAddressClear(address string) (IP string) {
 //
 // Português:
 //
-//  Atualiza o endereço atual do container
+//  Atualiza o endereço atual do container e executa as verificações de saúde
 //
 //  O endereço do servidor pode mudar quando o container é reiniciado
 //
@@ -102,66 +204,153 @@ func (e *Server) ipAddressClear(address string) (IP string) {
 //     IP: endereço IPV4 do container
 //     ready: true se o container está pronto para receber requisições
 func (e *Server) getAndUpdateThisInstanceAddress() (IP string, ready bool) {
-	var thisNode = e.memberList.LocalNode()
-	IP = e.ipAddressClear(thisNode.Addr.String())
-	ready = true
+	var thisNode, _ = e.getMember(e.localNodeName())
+	IP = thisNode.Address
+	ready = e.updateHealth()
 
 	return
 }
 
+// Init
+//
+// English:
+//
+//  Starts the node, joins the services found by DNS and starts the synchronism loop
+//
+//   Input:
+//     syncPort: port used by memberlist to synchronize the instances. Zero picks a free port
+//     servicesListNames: names of the services/containers of the other instances
+//
+//   Output:
+//     err: standard error object
+//
+//   Note:
+//     * The Set*() functions must be called before Init();
+//     * A failure of the first discovery doesn't fail Init(), the sync loop tries again with backoff,
+//       see SetExpectedMembers().
+//
+// Português:
+//
+//  Inicia o node, entra nos serviços encontrados por DNS e inicia o ciclo de sincronismo
+//
+//   Entrada:
+//     syncPort: porta usada pela memberlist para sincronizar as instâncias. Zero escolhe uma porta livre
+//     servicesListNames: nomes dos serviços/containers das demais instâncias
+//
+//   Saída:
+//     err: objeto de erro padrão
+//
+//   Nota:
+//     * As funções Set*() devem ser chamadas antes de Init();
+//     * Uma falha da primeira descoberta não faz Init() falhar, o ciclo de sincronismo tenta novamente
+//       com recuo, veja SetExpectedMembers().
 func (e *Server) Init(syncPort int, servicesListNames ...string) (err error) {
-	var ipAddress string
-
 	e.syncPort = syncPort
 	e.AddServersByName(servicesListNames...)
-	filter := &logutils.LevelFilter{
-		Levels:   []logutils.LogLevel{"DEBUG", "WARN", "ERROR"},
-		MinLevel: logutils.LogLevel("WARN"),
-		Writer:   os.Stderr,
+	// o histórico de nodes precisa existir antes de memberlist.Create(), pois o próprio node gera um
+	// evento de entrada durante a criação
+	e.loadNodeHistory()
+
+	// o ID do node é publicado nos metadados e precisa existir antes de memberlist.Create()
+	err = e.loadNodeID()
+	if err != nil {
+		e.getLogger().Error("node ID load failed", "error", err)
+		return
 	}
-	log.SetOutput(filter)
 
-	// inicializa a lista de PODs no service discover
-	var conf = memberlist.DefaultLANConfig()
-	e.memberList, err = memberlist.Create(conf)
+	// a posição dos contadores deste node é o ID do node, assim ela é a mesma depois de um reinício
+	e.getCounterStore().setSlot(e.NodeID())
+
+	// confere os metadados com o ID carregado, as tags definidas antes de Init() reservaram espaço
+	// apenas para um ID de até kNodeMetaReservedIDSize bytes
+	if e.getNodeMeta().fits(memberlist.MetaMaxSize) == false {
+		err = ErrNodeMetaTooLarge
+		e.getLogger().Error("node metadata exceeds the memberlist limit", "error", err)
+		return
+	}
+
+	// abre a porta gRPC antes da memberlist, pois a porta é publicada nos metadados do node
+	err = e.grpcListen()
 	if err != nil {
-		util.TraceToLog()
+		e.getLogger().Error("grpc listen failed", "error", err)
 		return
 	}
 
-	e.nodeNamesList = new(sync.Map)
+	// registra o coletor deste Server na cópia das métricas globais, que só as recebe depois de
+	// EnableMemberlistMetrics(), e abre a porta de métricas antes da memberlist, assim uma falha não
+	// deixa uma memberlist criada para fechar
+	err = e.metricsListen()
+	if err != nil {
+		e.getLogger().Error("metrics listen failed", "error", err)
+		_ = e.grpcListener.Close()
+		return
+	}
 
-	// inicializa o ciclo de troca de dados entre pods
-	e.syncBetweenInstancesTicker = time.NewTicker(kSyncBetweenPodsInterval)
+	// executa as verificações de saúde antes da memberlist, pois o resultado é publicado nos metadados
+	e.updateHealth()
 
-	err = e.DnsVerifyServices()
+	// inicializa a lista de PODs no service discover
+	var conf = e.newMemberlistConfig()
+	e.localName = conf.Name
+	conf.Events = &serverEventDelegate{server: e}
+	conf.Delegate = &serverDelegate{server: e}
+	e.broadcastQueue = e.newBroadcastQueue(conf.RetransmitMult)
+	e.memberList, err = memberlist.Create(conf)
 	if err != nil {
-		util.TraceToLog()
+		e.getLogger().Error("memberlist creation failed", "error", err)
+		_ = e.grpcListener.Close()
+		if e.metricsListener != nil {
+			_ = e.metricsListener.Close()
+		}
 		return
 	}
 
+	// com a porta zero, memberlist.Create() escolhe uma porta livre e a grava em conf.BindPort
+	e.syncPort = conf.BindPort
+
+	e.grpcServe()
+	e.metricsServe()
+
+	var thisNode, _ = e.getMember(e.localNodeName())
+	e.stateMutex.Lock()
+	e.thisNodeAddress = thisNode.Address
+	e.stateMutex.Unlock()
+
+	// inicializa o ciclo de troca de dados entre pods
+	e.syncBetweenInstancesTicker = time.NewTicker(e.getDnsCheckInterval())
+	e.syncBetweenInstancesStop = make(chan struct{})
+	e.syncBetweenInstancesDone = make(chan struct{})
+
+	// a falha da primeira descoberta não impede o início, por exemplo, quando os demais containers
+	// ainda não estão no DNS, e o ciclo de sincronismo tenta novamente com recuo
+	e.updateJoin()
+
 	go func(e *Server) {
-		defer func() {
-			log.Printf("bug: server aborted")
-		}()
+		var ipAddress string
+
+		defer close(e.syncBetweenInstancesDone)
 		for {
 			select {
+			case <-e.syncBetweenInstancesStop:
+				return
+
 			case <-e.syncBetweenInstancesTicker.C:
 
-				err = e.DnsVerifyServices()
-				if err != nil {
-					log.Printf("ticker.verifyDnsForServices().error: %v", err)
-					err = nil
-				}
+				e.updateJoin()
+
+				e.getKeyValueStore().prune(kKeyValueTombstoneTTL)
+				e.updateNodeHistory()
 
-				ipAddress, e.thisInstanceIsReady = e.getAndUpdateThisInstanceAddress()
-				if e.thisInstanceIsReady == false {
-					util.TraceToLog()
-					log.Printf("e.getAndUpdateThisInstanceAddress(): recusou")
-					continue
-				}
+				ipAddress, _ = e.getAndUpdateThisInstanceAddress()
 
+				e.stateMutex.Lock()
 				e.thisNodeAddress = ipAddress
+				e.stateMutex.Unlock()
+
+				e.updateLeader()
+				e.updatePubSub()
+				e.pruneGrpcConnections()
+				e.getUserEvents().prune()
 			}
 		}
 	}(e)
@@ -169,38 +358,31 @@ func (e *Server) Init(syncPort int, servicesListNames ...string) (err error) {
 	return
 }
 
+// DnsVerifyServices
+//
+// English:
+//
+//  Collects the addresses of the services added by AddServersByName() and of the providers added by
+//  AddDiscovery() and joins the addresses that are not members yet
+//
+//   Output:
+//     err: error of the last provider, only when no provider found any address
+//
+//   Note:
+//     * The sync loop calls this function adaptively, see SetExpectedMembers().
+//
+// Português:
+//
+//  Coleta os endereços dos serviços adicionados por AddServersByName() e dos provedores adicionados por
+//  AddDiscovery() e entra nos endereços que ainda não são membros
+//
+//   Saída:
+//     err: erro do último provedor, apenas quando nenhum provedor encontrou endereços
+//
+//   Nota:
+//     * O ciclo de sincronismo chama esta função de forma adaptativa, veja SetExpectedMembers().
 func (e *Server) DnsVerifyServices() (err error) {
-	var pass = false
-	var ipServiceList []net.IP
-	var ipServiceListAsString = make([]string, 0)
-
-	for _, serviceName := range e.serviceNameList {
-		ipServiceList, err = net.LookupIP(serviceName)
-		if err == nil {
-			pass = true
-		}
-
-		for _, nodeIP := range ipServiceList {
-			ipServiceListAsString = append(ipServiceListAsString, nodeIP.String())
-		}
-	}
-
-	if pass == false {
-		log.Print("nenhum servidor encontrado")
-		return
-	}
-
-	if len(ipServiceListAsString) == 0 {
-		return
-	}
-
-	_, err = e.memberList.Join(ipServiceListAsString)
-	if err != nil {
-		log.Printf("e.memberList.Join().error: %v", err)
-		err = nil
-		return
-	}
-
+	_, _, err = e.discoverAndJoin()
 	return
 }
 
@@ -216,53 +398,39 @@ func (e *Server) getMembersListToSyncDataAndManagerSyncList() (ipList []string,
 	// chave: nome do node / valor: ip do node
 	nodeAddedList = make(map[string]string)
 
-	if e.nodeNamesList == nil {
-		e.nodeNamesList = new(sync.Map)
+	if e.nodeHistory == nil {
+		e.nodeHistory = newNodeHistory()
 	}
 
-	var ipAddress string
-
-	// testa se um node tem nome registrado e um ip diferente do original
-	var originalIpAddress interface{}
-
 	// preenche a lista atual de membros
 	// chave: nome do node / valor: ip do node
 	var nodeActualMembersList = make(map[string]string)
-	for _, node := range e.memberList.Members() {
-		ipAddress = e.ipAddressClear(node.Address())
-		nodeActualMembersList[node.Name] = ipAddress
+	for _, node := range e.Members() {
+		nodeActualMembersList[node.Name] = node.Address
 
-		ipList = append(ipList, ipAddress)
+		ipList = append(ipList, node.Address)
 	}
 
 	// pega a lista de nodes desligados
 	// chave: nome do node / valor: ip do node
+	// os nodes marcados como removidos em definitivo não entram na lista
 	var found bool
-	e.nodeNamesList.Range(func(nodeName, nodeIpAddress interface{}) (continueLoop bool) {
-		_, found = nodeActualMembersList[nodeName.(string)]
-		if found == false {
-			nodeShutdownList[nodeName.(string)] = nodeIpAddress.(string)
+	for _, record := range e.nodeHistory.snapshot() {
+		_, found = nodeActualMembersList[record.Name]
+		if found == false && record.Status != NodeStatusGone {
+			nodeShutdownList[record.Name] = record.Address
 		}
+	}
 
-		continueLoop = true
-		return
-	})
-
-	// pega a lista de nodes adicionados
+	// pega a lista de nodes adicionados, ainda sem registro no histórico
 	// chave: nome do node / valor: ip do node
+	// a mudança de endereço de um node conhecido, pelo nome ou pelo ID, é publicada pelo delegate de
+	// eventos como NodeAddressChanged
 	for nodeName, nodeIpAddress := range nodeActualMembersList {
-		originalIpAddress, found = e.nodeNamesList.Load(nodeName)
+		_, found = e.nodeHistory.get(nodeName)
 		if found == false {
 			nodeAddedList[nodeName] = nodeIpAddress
 		}
-
-		// adiciona o node a lista permanente de nodes
-		e.nodeNamesList.Store(nodeName, nodeIpAddress)
-
-		// procura um bug ou falha de conceito
-		if originalIpAddress != nil && originalIpAddress.(string) != nodeActualMembersList[nodeName] {
-			log.Printf("bug: o endereço IP do node está variando para o node.name")
-		}
 	}
 
 	return
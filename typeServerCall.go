@@ -0,0 +1,311 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	//kCallTimeout
+	//
+	// English:
+	//
+	// Maximum time to wait for the answer of Call() when the context has no deadline.
+	//
+	// Português:
+	//
+	// Tempo máximo de espera pela resposta de Call() quando o contexto não tem prazo.
+	kCallTimeout = time.Second * 5
+)
+
+// ErrNodeNotAlive
+//
+// English:
+//
+//  Returned when the node is known but left the cluster, failed, is leaving or doesn't answer.
+//
+// Português:
+//
+//  Retornado quando o node é conhecido mas saiu do cluster, falhou, está saindo ou não responde.
+var ErrNodeNotAlive = errors.New("node is not alive")
+
+// ErrMethodNotFound
+//
+// English:
+//
+//  Returned when the node has no handler for the method, see Server.Handle().
+//
+// Português:
+//
+//  Retornado quando o node não tem uma função para o método, veja Server.Handle().
+var ErrMethodNotFound = errors.New("method not found")
+
+// ErrInvalidMethod
+//
+// English:
+//
+//  Returned when the name of the method is empty.
+//
+// Português:
+//
+//  Retornado quando o nome do método é vazio.
+var ErrInvalidMethod = errors.New("invalid method")
+
+// CallError
+//
+// English:
+//
+//  Error returned by the handler of the method on the remote node
+//
+//   Fields:
+//     Node: name of the node that answered
+//     Method: name of the method
+//     Message: text of the error returned by the handler
+//
+// Português:
+//
+//  Erro retornado pela função do método no node remoto
+//
+//   Campos:
+//     Node: nome do node que respondeu
+//     Method: nome do método
+//     Message: texto do erro retornado pela função
+type CallError struct {
+	Node    string
+	Method  string
+	Message string
+}
+
+// Error
+//
+// English:
+//
+//  Returns the text of the error
+//
+// Português:
+//
+//  Retorna o texto do erro
+func (e *CallError) Error() string {
+	return fmt.Sprintf("call %v on node %v failed: %v", e.Method, e.Node, e.Message)
+}
+
+// Handle
+//
+// English:
+//
+//  Registers the function that answers the calls of a method made by Call() on the other members
+//
+//   Input:
+//     method: name of the method
+//     handler: function that answers the calls. nil removes the method
+//
+//   Output:
+//     err: ErrInvalidMethod
+//
+//   Note:
+//     * The error returned by handler reaches the caller as *CallError.
+//
+// Português:
+//
+//  Registra a função que responde as chamadas de um método feitas por Call() nos demais membros
+//
+//   Entrada:
+//     method: nome do método
+//     handler: função que responde as chamadas. nil remove o método
+//
+//   Saída:
+//     err: ErrInvalidMethod
+//
+//   Nota:
+//     * O erro retornado por handler chega em quem chamou como *CallError.
+func (e *Server) Handle(method string, handler CommunicationHandler) (err error) {
+	if method == "" {
+		err = ErrInvalidMethod
+		return
+	}
+
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	if handler == nil {
+		delete(e.callHandlers, method)
+		return
+	}
+
+	if e.callHandlers == nil {
+		e.callHandlers = make(map[string]CommunicationHandler)
+	}
+	e.callHandlers[method] = handler
+	return
+}
+
+// Call
+//
+// English:
+//
+//  Calls a method on a member of the cluster and waits for the answer
+//
+//   Input:
+//     ctx: context of the call. When ctx has no deadline, kCallTimeout is used
+//     nodeName: name of the node, it may be this node
+//     method: name of the method registered by Handle() on the node
+//     payload: content of the call
+//
+//   Output:
+//     replay: answer of the handler
+//     err: ErrInvalidMethod, ErrNodeNotFound when the node is unknown, ErrNodeNotAlive when the node
+//          left, failed, is leaving or doesn't answer, ErrMethodNotFound, *CallError when the handler
+//          returns an error, context.DeadlineExceeded, context.Canceled or standard error object
+//
+//   Note:
+//     * The address is the one known by memberlist and the port is the gRPC port published in the
+//       metadata of the node;
+//     * The connection to each node is reused by all calls.
+//
+// Português:
+//
+//  Chama um método em um membro do cluster e espera pela resposta
+//
+//   Entrada:
+//     ctx: contexto da chamada. Quando ctx não tem prazo, kCallTimeout é usado
+//     nodeName: nome do node, pode ser este node
+//     method: nome do método registrado por Handle() no node
+//     payload: conteúdo da chamada
+//
+//   Saída:
+//     replay: resposta da função
+//     err: ErrInvalidMethod, ErrNodeNotFound quando o node é desconhecido, ErrNodeNotAlive quando o
+//          node saiu, falhou, está saindo ou não responde, ErrMethodNotFound, *CallError quando a
+//          função retorna um erro, context.DeadlineExceeded, context.Canceled ou objeto de erro
+//          padrão
+//
+//   Nota:
+//     * O endereço é o conhecido pela memberlist e a porta é a porta gRPC publicada nos metadados do
+//       node;
+//     * A conexão com cada node é reaproveitada por todas as chamadas.
+func (e *Server) Call(ctx context.Context, nodeName, method string, payload []byte) (replay []byte, err error) {
+	if method == "" {
+		err = ErrInvalidMethod
+		return
+	}
+
+	if member, found := e.getMember(nodeName); found == true && member.State == MemberLeaving {
+		err = ErrNodeNotAlive
+		return
+	}
+
+	var client grpcProto.SyncInstancesClient
+	client, err = e.GetGrpcClient(nodeName)
+	if errors.Is(err, ErrNodeNotFound) == true && e.nodeHistory != nil {
+		if _, found := e.nodeHistory.get(nodeName); found == true {
+			err = ErrNodeNotAlive
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	if _, ok := ctx.Deadline(); ok == false {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, kCallTimeout)
+		defer cancel()
+	}
+
+	var answer *grpcProto.CommunicationReplay
+	answer, err = client.GrpcFuncCommunication(ctx, &grpcProto.CommunicationRequest{
+		From:    e.localNodeName(),
+		Method:  method,
+		Payload: payload,
+	})
+	if err != nil {
+		err = callError(nodeName, method, err)
+		return
+	}
+
+	replay = answer.GetPayload()
+	return
+}
+
+// getCallHandler
+//
+// English:
+//
+//  Returns the function registered by Handle() for the method
+//
+// Português:
+//
+//  Retorna a função registrada por Handle() para o método
+func (e *Server) getCallHandler(method string) (handler CommunicationHandler) {
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	return e.callHandlers[method]
+}
+
+// pruneGrpcConnections
+//
+// English:
+//
+//  Closes the connections to addresses that are no longer used by the members, for example, after
+//  a node changed its address or left the cluster. Called by the synchronism loop
+//
+// Português:
+//
+//  Fecha as conexões com endereços que não são mais usados pelos membros, por exemplo, depois de um
+//  node mudar de endereço ou sair do cluster. Chamado pelo ciclo de sincronismo
+func (e *Server) pruneGrpcConnections() {
+	var addresses = make(map[string]bool)
+	for _, member := range e.Members() {
+		if member.GrpcPort != 0 {
+			addresses[net.JoinHostPort(member.Address, strconv.Itoa(member.GrpcPort))] = true
+		}
+	}
+
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	for address, connection := range e.grpcConnections {
+		if addresses[address] == false {
+			_ = connection.Close()
+			delete(e.grpcConnections, address)
+		}
+	}
+}
+
+// callError
+//
+// English:
+//
+//  Converts the gRPC status of a failed call into the errors documented by Call()
+//
+// Português:
+//
+//  Converte o status gRPC de uma chamada que falhou nos erros documentados por Call()
+func callError(nodeName, method string, err error) error {
+	var grpcStatus, ok = status.FromError(err)
+	if ok == false {
+		return err
+	}
+
+	switch grpcStatus.Code() {
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %v", ErrNodeNotAlive, grpcStatus.Message())
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	case codes.Canceled:
+		return context.Canceled
+	case codes.Unimplemented:
+		return fmt.Errorf("%w: %q", ErrMethodNotFound, method)
+	case codes.Unknown:
+		return &CallError{Node: nodeName, Method: method, Message: grpcStatus.Message()}
+	}
+
+	return err
+}
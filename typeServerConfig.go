@@ -0,0 +1,444 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"github.com/hashicorp/memberlist"
+	"log"
+	"net"
+	"time"
+)
+
+// NetworkProfile
+//
+// English:
+//
+//  Set of memberlist timings tuned for a type of network
+//
+// Português:
+//
+//  Conjunto de tempos da memberlist ajustados para um tipo de rede
+type NetworkProfile int
+
+const (
+	// NetworkProfileLAN
+	//
+	// English: local network, the default profile
+	//
+	// Português: rede local, o perfil padrão
+	NetworkProfileLAN NetworkProfile = iota
+
+	// NetworkProfileWAN
+	//
+	// English: wide area network, with larger timeouts and intervals
+	//
+	// Português: rede de longa distância, com tempos limite e intervalos maiores
+	NetworkProfileWAN
+
+	// NetworkProfileLocal
+	//
+	// English: loopback or single host, with smaller timeouts and intervals
+	//
+	// Português: loopback ou host único, com tempos limite e intervalos menores
+	NetworkProfileLocal
+)
+
+// SetBindAddress
+//
+// English:
+//
+//  Defines the address where the synchronism port listens. The port is defined by Init()
+//
+//   Input:
+//     address: IPv4 or IPv6 address, by default, 0.0.0.0, or :: when the preferred family is
+//              AddressFamilyIPv6
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o endereço onde a porta de sincronismo escuta. A porta é definida por Init()
+//
+//   Entrada:
+//     address: endereço IPv4 ou IPv6, por padrão, 0.0.0.0, ou :: quando a família preferida é
+//              AddressFamilyIPv6
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetBindAddress(address string) {
+	e.bindAddress = address
+}
+
+// SetAdvertiseAddress
+//
+// English:
+//
+//  Defines the address and port announced to the other members, useful behind NAT or when the bind
+//  address is 0.0.0.0
+//
+//   Input:
+//     address: IP address announced to the other members
+//     port: port announced to the other members. Zero uses the port defined by Init()
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o endereço e a porta anunciados aos demais membros, útil atrás de NAT ou quando o endereço
+//  de escuta é 0.0.0.0
+//
+//   Entrada:
+//     address: endereço IP anunciado aos demais membros
+//     port: porta anunciada aos demais membros. Zero usa a porta definida por Init()
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetAdvertiseAddress(address string, port int) {
+	e.advertiseAddress = address
+	e.advertisePort = port
+}
+
+// SetPreferredAddressFamily
+//
+// English:
+//
+//  Defines the preferred address family, used when a name resolves to addresses of both families
+//  and to choose the bind and advertise addresses
+//
+//   Input:
+//     family: AddressFamilyAny (default), AddressFamilyIPv4 or AddressFamilyIPv6
+//
+//   Note:
+//     * Must be called before Init();
+//     * With AddressFamilyIPv6 and no bind address, the node listens on :: and advertises the first
+//       global IPv6 address of the network interfaces;
+//     * Names with addresses of only one family are used as they are, whatever the preference.
+//
+// Português:
+//
+//  Define a família de endereços preferida, usada quando um nome resolve para endereços das duas
+//  famílias e para escolher os endereços de escuta e anunciado
+//
+//   Entrada:
+//     family: AddressFamilyAny (padrão), AddressFamilyIPv4 ou AddressFamilyIPv6
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * Com AddressFamilyIPv6 e sem endereço de escuta, o node escuta em :: e anuncia o primeiro
+//       endereço IPv6 global das interfaces de rede;
+//     * Nomes com endereços de apenas uma família são usados como estão, qualquer que seja a
+//       preferência.
+func (e *Server) SetPreferredAddressFamily(family AddressFamily) {
+	e.addressFamily = family
+}
+
+// SetNodeName
+//
+// English:
+//
+//  Defines the name of the node in the cluster, by default, the host name
+//
+//   Input:
+//     name: unique name of the node in the cluster
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o nome do node no cluster, por padrão, o nome do host
+//
+//   Entrada:
+//     name: nome único do node no cluster
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetNodeName(name string) {
+	e.nodeName = name
+}
+
+// SetNetworkProfile
+//
+// English:
+//
+//  Defines the set of timings used by memberlist
+//
+//   Input:
+//     profile: NetworkProfileLAN (default), NetworkProfileWAN or NetworkProfileLocal
+//
+//   Note:
+//     * Must be called before Init();
+//     * SetGossipInterval() and SetProbeInterval() take precedence over the profile.
+//
+// Português:
+//
+//  Define o conjunto de tempos usado pela memberlist
+//
+//   Entrada:
+//     profile: NetworkProfileLAN (padrão), NetworkProfileWAN ou NetworkProfileLocal
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * SetGossipInterval() e SetProbeInterval() têm precedência sobre o perfil.
+func (e *Server) SetNetworkProfile(profile NetworkProfile) {
+	e.networkProfile = profile
+}
+
+// SetGossipInterval
+//
+// English:
+//
+//  Defines the interval between gossip messages, overwriting the network profile
+//
+//   Input:
+//     interval: interval between gossip messages
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o intervalo entre as mensagens de fofoca, sobrescrevendo o perfil de rede
+//
+//   Entrada:
+//     interval: intervalo entre as mensagens de fofoca
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetGossipInterval(interval time.Duration) {
+	e.gossipInterval = interval
+}
+
+// SetProbeInterval
+//
+// English:
+//
+//  Defines the interval between failure detector probes, overwriting the network profile
+//
+//   Input:
+//     interval: interval between probes
+//     timeout: time to wait for the probe answer. Zero keeps the value of the network profile
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o intervalo entre as sondagens do detector de falhas, sobrescrevendo o perfil de rede
+//
+//   Entrada:
+//     interval: intervalo entre as sondagens
+//     timeout: tempo de espera pela resposta da sondagem. Zero mantém o valor do perfil de rede
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetProbeInterval(interval, timeout time.Duration) {
+	e.probeInterval = interval
+	e.probeTimeout = timeout
+}
+
+// SetDnsCheckInterval
+//
+// English:
+//
+//  Defines the interval between DNS checks of the services, by default, kSyncBetweenPodsInterval
+//
+//   Input:
+//     interval: interval between DNS checks
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o intervalo entre as verificações de DNS dos serviços, por padrão, kSyncBetweenPodsInterval
+//
+//   Entrada:
+//     interval: intervalo entre as verificações de DNS
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetDnsCheckInterval(interval time.Duration) {
+	e.dnsCheckInterval = interval
+}
+
+// getDnsCheckInterval
+//
+// English:
+//
+//  Returns the interval between DNS checks, applying the default value
+//
+// Português:
+//
+//  Retorna o intervalo entre as verificações de DNS, aplicando o valor padrão
+func (e *Server) getDnsCheckInterval() (interval time.Duration) {
+	if e.dnsCheckInterval <= 0 {
+		return kSyncBetweenPodsInterval
+	}
+
+	return e.dnsCheckInterval
+}
+
+// SetLogger
+//
+// English:
+//
+//  Defines the logger used by the server and by memberlist
+//
+//   Input:
+//     logger: structured logger, see NewLogger()
+//
+//   Note:
+//     * Must be called before Init();
+//     * Without a logger, the messages of the warn and error levels are written as text in
+//       os.Stderr;
+//     * The global log package is not changed.
+//
+// Português:
+//
+//  Define o logger usado pelo servidor e pela memberlist
+//
+//   Entrada:
+//     logger: logger estruturado, veja NewLogger()
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * Sem um logger, as mensagens dos níveis warn e error são escritas como texto em os.Stderr;
+//     * O pacote global log não é alterado.
+func (e *Server) SetLogger(logger Logger) {
+	e.logger = logger
+}
+
+// getLogger
+//
+// English:
+//
+//  Returns the logger defined by SetLogger() or the default logger
+//
+// Português:
+//
+//  Retorna o logger definido por SetLogger() ou o logger padrão
+func (e *Server) getLogger() (logger Logger) {
+	if e.logger == nil {
+		return defaultLogger
+	}
+
+	return e.logger
+}
+
+// newMemberlistConfig
+//
+// English:
+//
+//  Mounts the memberlist configuration from the network profile and the values defined by the
+//  Set*() functions
+//
+// Português:
+//
+//  Monta a configuração da memberlist a partir do perfil de rede e dos valores definidos pelas
+//  funções Set*()
+func (e *Server) newMemberlistConfig() (conf *memberlist.Config) {
+	switch e.networkProfile {
+	case NetworkProfileWAN:
+		conf = memberlist.DefaultWANConfig()
+	case NetworkProfileLocal:
+		conf = memberlist.DefaultLocalConfig()
+	default:
+		conf = memberlist.DefaultLANConfig()
+	}
+
+	conf.BindPort = e.syncPort
+	conf.AdvertisePort = e.syncPort
+
+	if e.bindAddress != "" {
+		conf.BindAddr = hostOf(e.bindAddress)
+	} else if e.addressFamily == AddressFamilyIPv6 {
+		conf.BindAddr = net.IPv6unspecified.String()
+	}
+
+	if e.advertiseAddress != "" {
+		conf.AdvertiseAddr = hostOf(e.advertiseAddress)
+	} else if conf.BindAddr == net.IPv6unspecified.String() {
+		// English: memberlist only detects a private address when the bind address is 0.0.0.0, so "::"
+		// would be announced as it is
+		// Português: a memberlist só detecta um endereço privado quando o endereço de escuta é 0.0.0.0,
+		// assim "::" seria anunciado como está
+		conf.AdvertiseAddr = interfaceAddress(AddressFamilyIPv6)
+	}
+
+	if e.advertisePort != 0 {
+		conf.AdvertisePort = e.advertisePort
+	}
+
+	if e.nodeName != "" {
+		conf.Name = e.nodeName
+	}
+
+	if e.gossipInterval > 0 {
+		conf.GossipInterval = e.gossipInterval
+	}
+
+	if e.probeInterval > 0 {
+		conf.ProbeInterval = e.probeInterval
+	}
+
+	if e.probeTimeout > 0 {
+		conf.ProbeTimeout = e.probeTimeout
+	}
+
+	if e.keyring != nil {
+		conf.Keyring = e.keyring
+	}
+
+	conf.Logger = log.New(&memberlistLogWriter{logger: e.getLogger()}, "", 0)
+
+	return
+}
+
+// SetResolver
+//
+// English:
+//
+//  Defines the resolver of the names added by AddServersByName()
+//
+//   Input:
+//     resolver: resolver of the names, see NewDnsResolver() and clustertest.FakeResolver
+//
+//   Note:
+//     * Must be called before Init();
+//     * By default, NewDnsResolver() is used with the name servers of the system, a timeout of 2
+//       seconds and no cache.
+//
+// Português:
+//
+//  Define o resolvedor dos nomes adicionados por AddServersByName()
+//
+//   Entrada:
+//     resolver: resolvedor dos nomes, veja NewDnsResolver() e clustertest.FakeResolver
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * Por padrão, NewDnsResolver() é usado com os servidores de nomes do sistema, um tempo limite
+//       de 2 segundos e sem cache.
+func (e *Server) SetResolver(resolver Resolver) {
+	e.resolver = resolver
+}
+
+// getResolver
+//
+// English:
+//
+//  Returns the resolver defined by SetResolver() or the default resolver
+//
+// Português:
+//
+//  Retorna o resolvedor definido por SetResolver() ou o resolvedor padrão
+func (e *Server) getResolver() (resolver Resolver) {
+	e.resolverOnce.Do(func() {
+		if e.resolver == nil {
+			e.resolver = NewDnsResolver("", 0, 0)
+		}
+	})
+
+	return e.resolver
+}
@@ -0,0 +1,289 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GCounter
+//
+// English:
+//
+//  Grow-only counter replicated in the whole cluster, see Server.GCounter()
+//
+// Português:
+//
+//  Contador que só cresce replicado em todo o cluster, veja Server.GCounter()
+type GCounter struct {
+	server *Server
+	key    counterKey
+}
+
+// Inc
+//
+// English:
+//
+//  Adds delta to the counter and sends the change to the other members
+//
+//   Input:
+//     delta: value added to the counter
+//
+// Português:
+//
+//  Soma delta ao contador e envia a alteração para os demais membros
+//
+//   Entrada:
+//     delta: valor somado ao contador
+func (e *GCounter) Inc(delta uint64) {
+	e.server.addCounter(e.key, delta, 0)
+}
+
+// Value
+//
+// English:
+//
+//  Returns the total of the cluster known by this node
+//
+//   Output:
+//     value: sum of the increments of all nodes
+//
+// Português:
+//
+//  Retorna o total do cluster conhecido por este node
+//
+//   Saída:
+//     value: soma dos incrementos de todos os nodes
+func (e *GCounter) Value() (value uint64) {
+	value, _ = e.server.getCounterStore().value(e.key)
+	return
+}
+
+// PNCounter
+//
+// English:
+//
+//  Counter replicated in the whole cluster that accepts increments and decrements, see
+//  Server.PNCounter()
+//
+// Português:
+//
+//  Contador replicado em todo o cluster que aceita incrementos e decrementos, veja
+//  Server.PNCounter()
+type PNCounter struct {
+	server *Server
+	key    counterKey
+}
+
+// Inc
+//
+// English:
+//
+//  Adds delta to the counter and sends the change to the other members
+//
+//   Input:
+//     delta: value added to the counter
+//
+// Português:
+//
+//  Soma delta ao contador e envia a alteração para os demais membros
+//
+//   Entrada:
+//     delta: valor somado ao contador
+func (e *PNCounter) Inc(delta uint64) {
+	e.server.addCounter(e.key, delta, 0)
+}
+
+// Dec
+//
+// English:
+//
+//  Subtracts delta from the counter and sends the change to the other members
+//
+//   Input:
+//     delta: value subtracted from the counter
+//
+// Português:
+//
+//  Subtrai delta do contador e envia a alteração para os demais membros
+//
+//   Entrada:
+//     delta: valor subtraído do contador
+func (e *PNCounter) Dec(delta uint64) {
+	e.server.addCounter(e.key, 0, delta)
+}
+
+// Value
+//
+// English:
+//
+//  Returns the total of the cluster known by this node
+//
+//   Output:
+//     value: sum of the increments minus the sum of the decrements of all nodes
+//
+// Português:
+//
+//  Retorna o total do cluster conhecido por este node
+//
+//   Saída:
+//     value: soma dos incrementos menos a soma dos decrementos de todos os nodes
+func (e *PNCounter) Value() (value int64) {
+	var increment, decrement = e.server.getCounterStore().value(e.key)
+	return int64(increment - decrement)
+}
+
+// GCounter
+//
+// English:
+//
+//  Returns the grow-only counter of the cluster with the given name
+//
+//   Input:
+//     name: name of the counter, the same on all nodes
+//
+//   Output:
+//     counter: handle of the counter, all handles with the same name share the same value
+//
+//   Note:
+//     * The counter is a CRDT: each node only changes its own slot and the slots are merged by the
+//       greatest value, so the total converges after partitions and never counts a change twice;
+//     * Changes are sent by gossip and the full state by the push/pull synchronism, so a restarted
+//       node gets the total back from the other members;
+//     * The slot of the node is the node ID, see SetNodeIDPath(), so a restart reuses the slot. The
+//       value written before the restart comes back from the other members and the changes of the
+//       new process are added to it, never overwritten;
+//     * Changes made before the first join only lose the value of the previous process when no
+//       member kept it.
+//
+// Português:
+//
+//  Retorna o contador do cluster que só cresce com o nome informado
+//
+//   Entrada:
+//     name: nome do contador, o mesmo em todos os nodes
+//
+//   Saída:
+//     counter: referência ao contador, todas as referências com o mesmo nome compartilham o mesmo
+//              valor
+//
+//   Nota:
+//     * O contador é um CRDT: cada node só altera a sua própria posição e as posições são mescladas
+//       pelo maior valor, assim o total converge depois de partições e nunca conta uma alteração
+//       duas vezes;
+//     * As alterações são enviadas por fofoca e o estado completo pelo sincronismo push/pull, assim
+//       um node reiniciado recebe o total de volta dos demais membros;
+//     * A posição do node é o ID do node, veja SetNodeIDPath(), assim um reinício reutiliza a
+//       posição. O valor escrito antes do reinício volta dos demais membros e as alterações do novo
+//       processo são somadas a ele, nunca sobrescritas;
+//     * Alterações feitas antes da primeira entrada no cluster só perdem o valor do processo anterior
+//       quando nenhum membro o guardou.
+func (e *Server) GCounter(name string) (counter *GCounter) {
+	return &GCounter{server: e, key: counterKey{Type: kCounterTypeGrow, Name: name}}
+}
+
+// PNCounter
+//
+// English:
+//
+//  Returns the counter of the cluster with the given name that accepts increments and decrements
+//
+//   Input:
+//     name: name of the counter, the same on all nodes
+//
+//   Output:
+//     counter: handle of the counter, all handles with the same name share the same value
+//
+//   Note:
+//     * The counter keeps a grow-only counter of increments and another of decrements, see
+//       GCounter();
+//     * PNCounter and GCounter with the same name are different counters.
+//
+// Português:
+//
+//  Retorna o contador do cluster com o nome informado que aceita incrementos e decrementos
+//
+//   Entrada:
+//     name: nome do contador, o mesmo em todos os nodes
+//
+//   Saída:
+//     counter: referência ao contador, todas as referências com o mesmo nome compartilham o mesmo
+//              valor
+//
+//   Nota:
+//     * O contador mantém um contador que só cresce de incrementos e outro de decrementos, veja
+//       GCounter();
+//     * PNCounter e GCounter com o mesmo nome são contadores diferentes.
+func (e *Server) PNCounter(name string) (counter *PNCounter) {
+	return &PNCounter{server: e, key: counterKey{Type: kCounterTypePositiveNegative, Name: name}}
+}
+
+// getCounterStore
+//
+// English:
+//
+//  Returns the replicated counters, creating them on first use
+//
+// Português:
+//
+//  Retorna os contadores replicados, criando-os no primeiro uso
+func (e *Server) getCounterStore() (store *counterStore) {
+	e.counterOnce.Do(func() {
+		e.counters = newCounterStore(time.Now().UnixNano())
+	})
+
+	return e.counters
+}
+
+// addCounter
+//
+// English:
+//
+//  Adds a local change to the slot of this node and sends the slot to the other members
+//
+// Português:
+//
+//  Adiciona uma alteração local à posição deste node e envia a posição para os demais membros
+func (e *Server) addCounter(key counterKey, increment, decrement uint64) {
+	var entry = e.getCounterStore().add(key, increment, decrement)
+	e.queueBroadcast(counterBroadcastName(entry), kGossipMessageCounter, entry)
+}
+
+// mergeCounterMessage
+//
+// English:
+//
+//  Merges a slot of a counter received by gossip
+//
+// Português:
+//
+//  Mescla uma posição de um contador recebida por fofoca
+func (e *Server) mergeCounterMessage(body []byte) {
+	var entry counterEntry
+	var err = json.Unmarshal(body, &entry)
+	if err != nil {
+		e.getLogger().Warn("invalid counter message", "error", err)
+		return
+	}
+
+	// English: as in mergeKeyValueMessage(), the slots that were new to this member are relayed
+	// Português: como em mergeKeyValueMessage(), as posições que eram novas para este membro são
+	// retransmitidas
+	if merged, changed := e.getCounterStore().merge(entry); changed == true {
+		e.queueBroadcast(counterBroadcastName(merged), kGossipMessageCounter, merged)
+	}
+}
+
+// counterBroadcastName
+//
+// English:
+//
+//  Returns the name of the broadcast of the slot, so a newer value of the slot replaces the older
+//  one in the queue
+//
+// Português:
+//
+//  Retorna o nome da transmissão da posição, assim um valor mais novo da posição substitui o mais
+//  antigo na fila
+func counterBroadcastName(entry counterEntry) (name string) {
+	return "counter:" + string(entry.Type) + ":" + entry.Name + ":" + entry.Slot
+}
@@ -0,0 +1,173 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// serverLocalState
+//
+// English:
+//
+//  Full state exchanged by the push/pull synchronism of memberlist
+//
+//   Fields:
+//     KeyValue: all entries of the replicated map, including the tombstones
+//     Counters: all slots of the replicated counters
+//
+// Português:
+//
+//  Estado completo trocado pelo sincronismo push/pull da memberlist
+//
+//   Campos:
+//     KeyValue: todas as entradas do mapa replicado, incluindo as lápides
+//     Counters: todas as posições dos contadores replicados
+type serverLocalState struct {
+	KeyValue []keyValueEntry `json:"kv,omitempty"`
+	Counters []counterEntry  `json:"c,omitempty"`
+}
+
+// serverDelegate
+//
+// English:
+//
+//  Implements memberlist.Delegate, publishes the metadata of the node and carries the replicated map
+//
+// Português:
+//
+//  Implementa memberlist.Delegate e publica os metadados do node
+type serverDelegate struct {
+	server *Server
+}
+
+// NodeMeta
+//
+// English:
+//
+//  Returns the encoded metadata of the node, limited to limit bytes. When the metadata exceeds the
+//  limit, only tags are left out, so the members never lose the ID, the gRPC port or the health
+//
+// Português:
+//
+//  Retorna os metadados codificados do node, limitados a limit bytes. Quando os metadados excedem o
+//  limite, apenas tags ficam de fora, assim os membros nunca perdem o ID, a porta gRPC ou a saúde
+func (e *serverDelegate) NodeMeta(limit int) []byte {
+	var meta, dropped = e.server.getNodeMeta().truncate(limit)
+	if len(dropped) != 0 {
+		e.server.getLogger().Warn("node metadata exceeds the limit, tags left out", "limit", limit, "tags", strings.Join(dropped, ","))
+	}
+
+	return meta.encode()
+}
+
+// NotifyMsg
+//
+// English:
+//
+//  Called by memberlist when a user message is received
+//
+// Português:
+//
+//  Chamado pela memberlist quando uma mensagem de usuário é recebida
+func (e *serverDelegate) NotifyMsg(message []byte) {
+	if len(message) == 0 {
+		return
+	}
+
+	// English: the slice may be reused by memberlist after the return
+	// Português: o slice pode ser reutilizado pela memberlist depois do retorno
+	var body = make([]byte, len(message)-1)
+	copy(body, message[1:])
+
+	switch gossipMessageType(message[0]) {
+	case kGossipMessageKeyValue:
+		e.server.mergeKeyValueMessage(body)
+	case kGossipMessageKeyringRequest:
+		e.server.handleKeyringRequest(body)
+	case kGossipMessageKeyringResponse:
+		e.server.handleKeyringResponse(body)
+	case kGossipMessageCounter:
+		e.server.mergeCounterMessage(body)
+	case kGossipMessageUserEvent:
+		e.server.mergeUserEventMessage(body)
+	default:
+		e.server.getLogger().Warn("unknown gossip message type", "type", message[0])
+	}
+}
+
+// GetBroadcasts
+//
+// English:
+//
+//  Called by memberlist to collect the user messages to be broadcast
+//
+// Português:
+//
+//  Chamado pela memberlist para coletar as mensagens de usuário a serem transmitidas
+func (e *serverDelegate) GetBroadcasts(overhead, limit int) [][]byte {
+	if e.server.broadcastQueue == nil {
+		return nil
+	}
+
+	return e.server.broadcastQueue.GetBroadcasts(overhead, limit)
+}
+
+// LocalState
+//
+// English:
+//
+//  Called by memberlist to send the local state in a push/pull synchronism
+//
+// Português:
+//
+//  Chamado pela memberlist para enviar o estado local em um sincronismo push/pull
+func (e *serverDelegate) LocalState(join bool) []byte {
+	var state = serverLocalState{
+		KeyValue: e.server.getKeyValueStore().snapshot(),
+		Counters: e.server.getCounterStore().snapshot(),
+	}
+
+	var data, err = json.Marshal(state)
+	if err != nil {
+		e.server.getLogger().Error("local state encoding failed", "error", err)
+		return nil
+	}
+
+	return data
+}
+
+// MergeRemoteState
+//
+// English:
+//
+//  Called by memberlist to merge the state received in a push/pull synchronism
+//
+// Português:
+//
+//  Chamado pela memberlist para mesclar o estado recebido em um sincronismo push/pull
+func (e *serverDelegate) MergeRemoteState(buffer []byte, join bool) {
+	if len(buffer) == 0 {
+		return
+	}
+
+	var state serverLocalState
+	var err = json.Unmarshal(buffer, &state)
+	if err != nil {
+		e.server.getLogger().Warn("invalid remote state", "error", err)
+		return
+	}
+
+	var store = e.server.getKeyValueStore()
+	for _, entry := range state.KeyValue {
+		store.merge(entry)
+	}
+
+	// English: the slot of this node rebased on the value of the previous process is sent at once
+	// Português: a posição deste node refeita sobre o valor do processo anterior é enviada na hora
+	var counters = e.server.getCounterStore()
+	for _, entry := range state.Counters {
+		if merged, changed := counters.merge(entry); changed == true && counters.isLocal(merged) == true {
+			e.server.queueBroadcast(counterBroadcastName(merged), kGossipMessageCounter, merged)
+		}
+	}
+}
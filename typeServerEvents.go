@@ -0,0 +1,231 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"bytes"
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/memberlist"
+	"sync"
+	"time"
+)
+
+const (
+	//kNodeEventBufferSize
+	//
+	// English:
+	//
+	// Default size of the channel returned by SubscribeNodeEvents() when bufferSize is less than one.
+	//
+	// Português:
+	//
+	// Tamanho padrão do canal retornado por SubscribeNodeEvents() quando bufferSize é menor que um.
+	kNodeEventBufferSize = 64
+)
+
+// SubscribeNodeEvents
+//
+// English:
+//
+//  Returns a channel that receives the membership changes of the cluster
+//
+//   Input:
+//     bufferSize: size of the channel buffer. Values less than one use kNodeEventBufferSize
+//
+//   Output:
+//     events: channel of membership changes
+//     unsubscribe: removes the subscription and closes the channel
+//
+//   Note:
+//     * The events come from the memberlist event delegate and must not block it, so, when the
+//       channel is full, the event is discarded and a warning is logged;
+//     * Subscribe before Init() to receive the join event of the node itself.
+//
+// Português:
+//
+//  Retorna um canal que recebe as mudanças de membros do cluster
+//
+//   Entrada:
+//     bufferSize: tamanho do buffer do canal. Valores menores que um usam kNodeEventBufferSize
+//
+//   Saída:
+//     events: canal de mudanças de membros
+//     unsubscribe: remove a inscrição e fecha o canal
+//
+//   Nota:
+//     * Os eventos vêm do delegate de eventos da memberlist e não podem bloqueá-lo, por isto, quando
+//       o canal está cheio, o evento é descartado e um aviso é registrado no log;
+//     * Inscreva-se antes de Init() para receber o evento de entrada do próprio node.
+func (e *Server) SubscribeNodeEvents(bufferSize int) (events <-chan NodeEvent, unsubscribe func()) {
+	if bufferSize < 1 {
+		bufferSize = kNodeEventBufferSize
+	}
+
+	var channel = make(chan NodeEvent, bufferSize)
+
+	e.nodeEventMutex.Lock()
+	defer e.nodeEventMutex.Unlock()
+
+	if e.nodeEventSubscribers == nil {
+		e.nodeEventSubscribers = make(map[chan NodeEvent]struct{})
+	}
+	e.nodeEventSubscribers[channel] = struct{}{}
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			e.nodeEventMutex.Lock()
+			defer e.nodeEventMutex.Unlock()
+
+			delete(e.nodeEventSubscribers, channel)
+			close(channel)
+		})
+	}
+
+	events = channel
+	return
+}
+
+// publishNodeEvent
+//
+// English:
+//
+//  Delivers the event to all subscribers without blocking
+//
+// Português:
+//
+//  Entrega o evento a todos os inscritos sem bloquear
+func (e *Server) publishNodeEvent(event NodeEvent) {
+	e.getMetrics().IncrCounterWithLabels([]string{"node", "event"}, 1, []metrics.Label{{Name: "type", Value: event.Type.String()}})
+
+	e.nodeEventMutex.Lock()
+	defer e.nodeEventMutex.Unlock()
+
+	for channel := range e.nodeEventSubscribers {
+		select {
+		case channel <- event:
+		default:
+			e.getLogger().Warn("node event discarded, subscriber channel is full", "type", event.Type, "node", event.Name)
+		}
+	}
+}
+
+// serverEventDelegate
+//
+// English:
+//
+//  Implements memberlist.EventDelegate and converts the memberlist notifications into NodeEvent
+//
+// Português:
+//
+//  Implementa memberlist.EventDelegate e converte as notificações da memberlist em NodeEvent
+type serverEventDelegate struct {
+	server *Server
+}
+
+// NotifyJoin
+//
+// English:
+//
+//  Called by memberlist when a node joins the cluster
+//
+// Português:
+//
+//  Chamado pela memberlist quando um node entra no cluster
+func (e *serverEventDelegate) NotifyJoin(node *memberlist.Node) {
+	var event = e.newEvent(NodeJoined, node)
+
+	var previous, sameNode = e.server.nodeHistory.alive(node.Name, event.ID, event.Address, event.Time)
+	if sameNode == true && previous.Address != event.Address {
+		event.Type = NodeAddressChanged
+		event.PreviousAddress = previous.Address
+	}
+
+	e.server.storeMember(node)
+	e.server.updateRingNode(node.Name, decodeNodeMeta(node.Meta).RingWeight, true)
+	e.server.publishNodeEvent(event)
+}
+
+// NotifyLeave
+//
+// English:
+//
+//  Called by memberlist when a node leaves the cluster or is declared dead
+//
+// Português:
+//
+//  Chamado pela memberlist quando um node sai do cluster ou é declarado morto
+func (e *serverEventDelegate) NotifyLeave(node *memberlist.Node) {
+	var eventType = NodeFailed
+	// a memberlist não preenche node.State, a saída graciosa é conhecida pela flag Leaving dos metadados
+	if decodeNodeMeta(node.Meta).Leaving == true {
+		eventType = NodeLeft
+	}
+
+	var event = e.newEvent(eventType, node)
+	var status = NodeStatusFailed
+	if eventType == NodeLeft {
+		status = NodeStatusLeft
+	}
+
+	e.server.nodeHistory.down(node.Name, status, event.Time)
+	e.server.removeMember(node.Name)
+	e.server.updateRingNode(node.Name, 0, false)
+	e.server.publishNodeEvent(event)
+}
+
+// NotifyUpdate
+//
+// English:
+//
+//  Called by memberlist when a node changes its address or metadata. Publishes NodeAddressChanged
+//  or, when only the metadata changed, NodeUpdated
+//
+// Português:
+//
+//  Chamado pela memberlist quando um node muda o endereço ou os metadados. Publica
+//  NodeAddressChanged ou, quando apenas os metadados mudaram, NodeUpdated
+func (e *serverEventDelegate) NotifyUpdate(node *memberlist.Node) {
+	var event = e.newEvent(NodeAddressChanged, node)
+
+	var previous, sameNode = e.server.nodeHistory.alive(node.Name, event.ID, event.Address, event.Time)
+	var previousMember, _ = e.server.storeMember(node)
+	e.server.updateRingNode(node.Name, decodeNodeMeta(node.Meta).RingWeight, true)
+	if sameNode == true && previous.Address != event.Address {
+		event.PreviousAddress = previous.Address
+		e.server.publishNodeEvent(event)
+		return
+	}
+
+	if bytes.Equal(previousMember.Meta, node.Meta) == true {
+		return
+	}
+
+	event.Type = NodeUpdated
+	e.server.publishNodeEvent(event)
+}
+
+// newEvent
+//
+// English:
+//
+//  Copies the data of the memberlist node into a new event
+//
+// Português:
+//
+//  Copia os dados do node da memberlist para um novo evento
+func (e *serverEventDelegate) newEvent(eventType NodeEventType, node *memberlist.Node) (event NodeEvent) {
+	var meta = make([]byte, len(node.Meta))
+	copy(meta, node.Meta)
+
+	var decoded = decodeNodeMeta(node.Meta)
+	event = NodeEvent{
+		Type:    eventType,
+		Name:    node.Name,
+		ID:      decoded.ID,
+		Address: e.server.ipAddressClear(node.Address()),
+		Family:  addressFamilyOfIP(node.Addr),
+		Meta:    meta,
+		Tags:    decoded.Tags,
+		Time:    time.Now(),
+	}
+	return
+}
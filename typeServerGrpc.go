@@ -0,0 +1,420 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"errors"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"net"
+	"strconv"
+)
+
+// ErrNodeNotFound
+//
+// English:
+//
+//  Returned when the node name is not an alive member of the cluster.
+//
+// Português:
+//
+//  Retornado quando o nome do node não é um membro ativo do cluster.
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrGrpcPortUnknown
+//
+// English:
+//
+//  Returned when the node did not publish the port of its gRPC server.
+//
+// Português:
+//
+//  Retornado quando o node não publicou a porta do seu servidor gRPC.
+var ErrGrpcPortUnknown = errors.New("grpc port of the node is unknown")
+
+// ErrCommunicationHandlerNotDefined
+//
+// English:
+//
+//  Returned when the node receives a message and SetCommunicationHandler() was not called.
+//
+// Português:
+//
+//  Retornado quando o node recebe uma mensagem e SetCommunicationHandler() não foi chamado.
+var ErrCommunicationHandlerNotDefined = errors.New("communication handler not defined")
+
+// CommunicationHandler
+//
+// English:
+//
+//  Function that receives the messages sent by SendMessage() from the other instances
+//
+//   Input:
+//     ctx: context of the gRPC call
+//     from: name of the node that sent the message
+//     payload: content of the message
+//
+//   Output:
+//     replay: content of the answer
+//     err: standard error object, sent to the caller
+//
+// Português:
+//
+//  Função que recebe as mensagens enviadas por SendMessage() das demais instâncias
+//
+//   Entrada:
+//     ctx: contexto da chamada gRPC
+//     from: nome do node que enviou a mensagem
+//     payload: conteúdo da mensagem
+//
+//   Saída:
+//     replay: conteúdo da resposta
+//     err: objeto de erro padrão, enviado para quem chamou
+type CommunicationHandler func(ctx context.Context, from string, payload []byte) (replay []byte, err error)
+
+// SetGrpcPort
+//
+// English:
+//
+//  Defines the port of the gRPC server of the SyncInstances service
+//
+//   Input:
+//     port: port of the gRPC server. Zero, the default value, picks a free port
+//
+//   Note:
+//     * Must be called before Init();
+//     * The port is published to the other members by the node metadata.
+//
+// Português:
+//
+//  Define a porta do servidor gRPC do serviço SyncInstances
+//
+//   Entrada:
+//     port: porta do servidor gRPC. Zero, o valor padrão, escolhe uma porta livre
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * A porta é publicada para os demais membros pelos metadados do node.
+func (e *Server) SetGrpcPort(port int) {
+	e.grpcPort = port
+}
+
+// SetCommunicationHandler
+//
+// English:
+//
+//  Defines the function that receives the messages sent by the other instances
+//
+//   Input:
+//     handler: function that receives the messages
+//
+// Português:
+//
+//  Define a função que recebe as mensagens enviadas pelas demais instâncias
+//
+//   Entrada:
+//     handler: função que recebe as mensagens
+func (e *Server) SetCommunicationHandler(handler CommunicationHandler) {
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	e.communicationHandler = handler
+}
+
+// GetGrpcClient
+//
+// English:
+//
+//  Returns a client of the SyncInstances service of a member of the cluster
+//
+//   Input:
+//     nodeName: name of the node, as returned by memberlist
+//
+//   Output:
+//     client: gRPC client. The connection is reused by the next calls
+//     err: ErrNodeNotFound, ErrGrpcPortUnknown or standard error object
+//
+// Português:
+//
+//  Retorna um cliente do serviço SyncInstances de um membro do cluster
+//
+//   Entrada:
+//     nodeName: nome do node, como retornado pela memberlist
+//
+//   Saída:
+//     client: cliente gRPC. A conexão é reaproveitada pelas próximas chamadas
+//     err: ErrNodeNotFound, ErrGrpcPortUnknown ou objeto de erro padrão
+func (e *Server) GetGrpcClient(nodeName string) (client grpcProto.SyncInstancesClient, err error) {
+	var address string
+	address, err = e.getGrpcAddress(nodeName)
+	if err != nil {
+		return
+	}
+
+	var connection *grpc.ClientConn
+	connection, err = e.getGrpcConnection(address)
+	if err != nil {
+		return
+	}
+
+	client = grpcProto.NewSyncInstancesClient(connection)
+	return
+}
+
+// InstanceIsReady
+//
+// English:
+//
+//  Asks a member of the cluster if it is ready to receive requests
+//
+//   Input:
+//     ctx: context of the gRPC call
+//     nodeName: name of the node
+//
+//   Output:
+//     ready: true if the node is ready
+//     err: standard error object
+//
+// Português:
+//
+//  Pergunta a um membro do cluster se ele está pronto para receber requisições
+//
+//   Entrada:
+//     ctx: contexto da chamada gRPC
+//     nodeName: nome do node
+//
+//   Saída:
+//     ready: true se o node está pronto
+//     err: objeto de erro padrão
+func (e *Server) InstanceIsReady(ctx context.Context, nodeName string) (ready bool, err error) {
+	var client grpcProto.SyncInstancesClient
+	client, err = e.GetGrpcClient(nodeName)
+	if err != nil {
+		return
+	}
+
+	var replay *grpcProto.InstanceIsReadyReplay
+	replay, err = client.GrpcFuncInstanceIsReady(ctx, &grpcProto.Empty{})
+	if err != nil {
+		return
+	}
+
+	ready = replay.GetIsReady()
+	return
+}
+
+// SendMessage
+//
+// English:
+//
+//  Sends a message to a member of the cluster and waits for the answer
+//
+//   Input:
+//     ctx: context of the gRPC call
+//     nodeName: name of the node
+//     payload: content of the message
+//
+//   Output:
+//     replay: content of the answer
+//     err: standard error object
+//
+// Português:
+//
+//  Envia uma mensagem para um membro do cluster e espera pela resposta
+//
+//   Entrada:
+//     ctx: contexto da chamada gRPC
+//     nodeName: nome do node
+//     payload: conteúdo da mensagem
+//
+//   Saída:
+//     replay: conteúdo da resposta
+//     err: objeto de erro padrão
+func (e *Server) SendMessage(ctx context.Context, nodeName string, payload []byte) (replay []byte, err error) {
+	var client grpcProto.SyncInstancesClient
+	client, err = e.GetGrpcClient(nodeName)
+	if err != nil {
+		return
+	}
+
+	var answer *grpcProto.CommunicationReplay
+	answer, err = client.GrpcFuncCommunication(ctx, &grpcProto.CommunicationRequest{
+		From:    e.localNodeName(),
+		Payload: payload,
+	})
+	if err != nil {
+		return
+	}
+
+	replay = answer.GetPayload()
+	return
+}
+
+// getCommunicationHandler
+//
+// English:
+//
+//  Returns the function defined by SetCommunicationHandler()
+//
+// Português:
+//
+//  Retorna a função definida por SetCommunicationHandler()
+func (e *Server) getCommunicationHandler() (handler CommunicationHandler) {
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	return e.communicationHandler
+}
+
+// getGrpcAddress
+//
+// English:
+//
+//  Returns the host:port address of the gRPC server of a member, using the IP address known by
+//  memberlist and the port published in the metadata
+//
+// Português:
+//
+//  Retorna o endereço host:porta do servidor gRPC de um membro, usando o endereço IP conhecido pela
+//  memberlist e a porta publicada nos metadados
+func (e *Server) getGrpcAddress(nodeName string) (address string, err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	var member, found = e.getMember(nodeName)
+	if found == false {
+		err = ErrNodeNotFound
+		return
+	}
+
+	var port = decodeNodeMeta(member.Meta).GrpcPort
+	if port == 0 {
+		err = ErrGrpcPortUnknown
+		return
+	}
+
+	address = net.JoinHostPort(member.Address, strconv.Itoa(port))
+	return
+}
+
+// getGrpcConnection
+//
+// English:
+//
+//  Returns the connection to the address, creating it on first use
+//
+// Português:
+//
+//  Retorna a conexão com o endereço, criando-a no primeiro uso
+func (e *Server) getGrpcConnection(address string) (connection *grpc.ClientConn, err error) {
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	if e.grpcConnections == nil {
+		e.grpcConnections = make(map[string]*grpc.ClientConn)
+	}
+
+	var found bool
+	connection, found = e.grpcConnections[address]
+	if found == true {
+		return
+	}
+
+	connection, err = grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return
+	}
+
+	e.grpcConnections[address] = connection
+	return
+}
+
+// grpcListen
+//
+// English:
+//
+//  Opens the port of the gRPC server and publishes it in the metadata. Must run before
+//  memberlist.Create(), so the first alive message already carries the port
+//
+// Português:
+//
+//  Abre a porta do servidor gRPC e a publica nos metadados. Deve rodar antes de memberlist.Create(),
+//  assim a primeira mensagem de vida já leva a porta
+func (e *Server) grpcListen() (err error) {
+	e.grpcListener, err = net.Listen("tcp", net.JoinHostPort(hostOf(e.bindAddress), strconv.Itoa(e.grpcPort)))
+	if err != nil {
+		return
+	}
+
+	var port = e.grpcListener.Addr().(*net.TCPAddr).Port
+	err = e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.GrpcPort = port
+	})
+	if err != nil {
+		_ = e.grpcListener.Close()
+		return
+	}
+
+	e.grpcServer = grpc.NewServer()
+	grpcProto.RegisterSyncInstancesServer(e.grpcServer, &grpcServer{server: e})
+	return
+}
+
+// grpcServe
+//
+// English:
+//
+//  Serves the gRPC requests until grpcStop()
+//
+// Português:
+//
+//  Atende as requisições gRPC até grpcStop()
+func (e *Server) grpcServe() {
+	go func(e *Server) {
+		var err = e.grpcServer.Serve(e.grpcListener)
+		if err != nil {
+			e.getLogger().Error("grpc server stopped", "error", err)
+		}
+	}(e)
+}
+
+// grpcStop
+//
+// English:
+//
+//  Stops the gRPC server, waiting for the calls in progress until ctx is done, and closes the
+//  client connections
+//
+// Português:
+//
+//  Para o servidor gRPC, esperando as chamadas em andamento até ctx terminar, e fecha as conexões
+//  de cliente
+func (e *Server) grpcStop(ctx context.Context) {
+	// English: GracefulStop() waits for the pubsub streams, which only end when the subscriber leaves
+	// Português: GracefulStop() espera pelos streams de pubsub, que só terminam quando o inscrito sai
+	e.getPubSub().stop()
+
+	if e.grpcServer != nil {
+		var stopped = make(chan struct{})
+		go func() {
+			e.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			e.grpcServer.Stop()
+		}
+	}
+
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	for address, connection := range e.grpcConnections {
+		_ = connection.Close()
+		delete(e.grpcConnections, address)
+	}
+}
@@ -0,0 +1,226 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// AddReadinessCheck
+//
+// English:
+//
+//  Registers a check that must pass for the instance to receive requests
+//
+//   Input:
+//     name: name of the check, reported when it fails
+//     timeout: time limit of the check. Zero uses kHealthCheckTimeout
+//     function: check function
+//
+//   Note:
+//     * The checks run at each cycle of the synchronism loop;
+//     * The result is published to the other members by the node metadata and by
+//       grpcFuncInstanceIsReady.
+//
+// Português:
+//
+//  Registra uma verificação que deve passar para a instância receber requisições
+//
+//   Entrada:
+//     name: nome da verificação, informado quando ela falha
+//     timeout: tempo limite da verificação. Zero usa kHealthCheckTimeout
+//     function: função de verificação
+//
+//   Nota:
+//     * As verificações rodam a cada ciclo do laço de sincronismo;
+//     * O resultado é publicado para os demais membros pelos metadados do node e por
+//       grpcFuncInstanceIsReady.
+func (e *Server) AddReadinessCheck(name string, timeout time.Duration, function HealthCheckFunc) {
+	e.healthMutex.Lock()
+	defer e.healthMutex.Unlock()
+
+	e.readinessChecks = append(e.readinessChecks, healthCheck{name: name, timeout: timeout, function: function})
+}
+
+// AddLivenessCheck
+//
+// English:
+//
+//  Registers a check that must pass for the instance to be considered alive. A failed liveness
+//  check also makes the instance not ready
+//
+//   Input:
+//     name: name of the check, reported when it fails
+//     timeout: time limit of the check. Zero uses kHealthCheckTimeout
+//     function: check function
+//
+// Português:
+//
+//  Registra uma verificação que deve passar para a instância ser considerada viva. Uma verificação
+//  de vida com falha também deixa a instância não pronta
+//
+//   Entrada:
+//     name: nome da verificação, informado quando ela falha
+//     timeout: tempo limite da verificação. Zero usa kHealthCheckTimeout
+//     function: função de verificação
+func (e *Server) AddLivenessCheck(name string, timeout time.Duration, function HealthCheckFunc) {
+	e.healthMutex.Lock()
+	defer e.healthMutex.Unlock()
+
+	e.livenessChecks = append(e.livenessChecks, healthCheck{name: name, timeout: timeout, function: function})
+}
+
+// ReadyMembers
+//
+// English:
+//
+//  Returns the names of the alive members that published themselves as ready, including this node
+//
+// Português:
+//
+//  Retorna os nomes dos membros ativos que se publicaram como prontos, incluindo este node
+func (e *Server) ReadyMembers() (names []string) {
+	names = make([]string, 0)
+	for _, member := range e.Members() {
+		if member.Ready == true {
+			names = append(names, member.Name)
+		}
+	}
+
+	return
+}
+
+// runHealthChecks
+//
+// English:
+//
+//  Runs all checks in parallel
+//
+//   Output:
+//     ready: true if all readiness and liveness checks passed
+//     live: true if all liveness checks passed
+//     failedChecks: names of the checks that failed
+//
+// Português:
+//
+//  Executa todas as verificações em paralelo
+//
+//   Saída:
+//     ready: true se todas as verificações de prontidão e de vida passaram
+//     live: true se todas as verificações de vida passaram
+//     failedChecks: nomes das verificações que falharam
+func (e *Server) runHealthChecks() (ready, live bool, failedChecks []string) {
+	e.healthMutex.Lock()
+	var readinessChecks = append([]healthCheck{}, e.readinessChecks...)
+	var livenessChecks = append([]healthCheck{}, e.livenessChecks...)
+	e.healthMutex.Unlock()
+
+	var readinessErrors = make([]error, len(readinessChecks))
+	var livenessErrors = make([]error, len(livenessChecks))
+
+	var wg sync.WaitGroup
+	for i := range readinessChecks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			readinessErrors[i] = readinessChecks[i].run()
+		}(i)
+	}
+	for i := range livenessChecks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			livenessErrors[i] = livenessChecks[i].run()
+		}(i)
+	}
+	wg.Wait()
+
+	live = true
+	failedChecks = make([]string, 0)
+	for i, err := range livenessErrors {
+		if err != nil {
+			live = false
+			failedChecks = append(failedChecks, livenessChecks[i].name)
+		}
+	}
+
+	ready = live
+	for i, err := range readinessErrors {
+		if err != nil {
+			ready = false
+			failedChecks = append(failedChecks, readinessChecks[i].name)
+		}
+	}
+
+	return
+}
+
+// updateHealth
+//
+// English:
+//
+//  Runs the checks, stores the result and, when it changes, publishes it to the other members
+//
+//   Output:
+//     ready: true if the instance is ready to receive requests
+//
+// Português:
+//
+//  Executa as verificações, guarda o resultado e, quando ele muda, o publica para os demais membros
+//
+//   Saída:
+//     ready: true se a instância está pronta para receber requisições
+func (e *Server) updateHealth() (ready bool) {
+	var live bool
+	var failedChecks []string
+	ready, live, failedChecks = e.runHealthChecks()
+
+	e.stateMutex.Lock()
+	var changed = e.thisInstanceIsReady != ready || e.thisInstanceIsLive != live
+	e.thisInstanceIsReady = ready
+	e.thisInstanceIsLive = live
+	e.failedChecks = failedChecks
+	e.stateMutex.Unlock()
+
+	if changed == false {
+		return
+	}
+
+	var write = e.getLogger().Info
+	if ready == false {
+		write = e.getLogger().Warn
+	}
+	write("health changed", "ready", ready, "live", live, "failedChecks", strings.Join(failedChecks, ","))
+	e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.Ready = ready
+		meta.Live = live
+	})
+
+	if e.memberList == nil {
+		return
+	}
+
+	var err = e.memberList.UpdateNode(e.getDnsCheckInterval())
+	if err != nil {
+		e.getLogger().Warn("node metadata update failed", "error", err)
+	}
+
+	return
+}
+
+// getHealth
+//
+// English:
+//
+//  Returns the result of the last run of the checks
+//
+// Português:
+//
+//  Retorna o resultado da última execução das verificações
+func (e *Server) getHealth() (ready, live bool, failedChecks []string) {
+	e.stateMutex.RLock()
+	defer e.stateMutex.RUnlock()
+
+	failedChecks = append([]string{}, e.failedChecks...)
+	return e.thisInstanceIsReady, e.thisInstanceIsLive, failedChecks
+}
@@ -0,0 +1,352 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//kJoinMaxInterval
+	//
+	// English:
+	//
+	// Interval between discoveries when the cluster has the expected size, and the limit of the
+	// backoff after failures.
+	//
+	// Português:
+	//
+	// Intervalo entre descobertas quando o cluster tem o tamanho esperado, e o limite do recuo após
+	// falhas.
+	kJoinMaxInterval = 30 * time.Second
+)
+
+// SetExpectedMembers
+//
+// English:
+//
+//  Defines the expected size of the cluster, this instance included.
+//
+//   Input:
+//     count: expected number of members. Zero, the default value, uses the number of addresses found
+//            by the last discovery
+//
+//   Note:
+//     * While the cluster is smaller than expected, the discovery runs at each SetDnsCheckInterval();
+//     * With the expected size, the discovery slows down to once every 30 seconds;
+//     * After failures, the interval doubles at each failure, with jitter, up to 30 seconds.
+//
+// Português:
+//
+//  Define o tamanho esperado do cluster, esta instância incluída.
+//
+//   Entrada:
+//     count: número esperado de membros. Zero, o valor padrão, usa o número de endereços encontrados
+//            pela última descoberta
+//
+//   Nota:
+//     * Enquanto o cluster é menor que o esperado, a descoberta roda a cada SetDnsCheckInterval();
+//     * Com o tamanho esperado, a descoberta desacelera para uma vez a cada 30 segundos;
+//     * Após falhas, o intervalo dobra a cada falha, com variação aleatória, até 30 segundos.
+func (e *Server) SetExpectedMembers(count int) {
+	e.expectedMembers = count
+}
+
+// getExpectedMembers
+//
+// English:
+//
+//  Returns the expected size of the cluster
+//
+// Português:
+//
+//  Retorna o tamanho esperado do cluster
+func (e *Server) getExpectedMembers() (count int) {
+	if e.expectedMembers > 0 {
+		return e.expectedMembers
+	}
+
+	return e.joinDiscovered
+}
+
+// updateJoin
+//
+// English:
+//
+//  Runs the discovery when it is due and schedules the next one.
+//
+//  A cluster smaller than expected runs the discovery at each tick, unless the last attempt failed.
+//
+//   Note:
+//     * Called only by Init(), before the sync loop starts, and by the sync loop goroutine.
+//
+// Português:
+//
+//  Executa a descoberta quando ela está na hora e agenda a próxima.
+//
+//  Um cluster menor que o esperado executa a descoberta a cada ciclo, a não ser que a última
+//  tentativa tenha falhado.
+//
+//   Nota:
+//     * Chamado apenas por Init(), antes do início do ciclo de sincronismo, e pela goroutine do ciclo
+//       de sincronismo.
+func (e *Server) updateJoin() {
+	var now = time.Now()
+	var incomplete = len(e.Members()) < e.getExpectedMembers()
+
+	if now.Before(e.joinNextAttempt) && (incomplete == false || e.joinFailures != 0) {
+		return
+	}
+
+	var discovered, joinErr, err = e.discoverAndJoin()
+	if err != nil || joinErr != nil {
+		e.joinFailures += 1
+		if err != nil {
+			e.getLogger().Warn("service discovery failed", "error", err, "failures", e.joinFailures)
+		}
+
+		var backoff = e.getDnsCheckInterval()
+		for i := 1; i < e.joinFailures && backoff < kJoinMaxInterval; i += 1 {
+			backoff *= 2
+		}
+		if backoff > kJoinMaxInterval {
+			backoff = kJoinMaxInterval
+		}
+
+		e.joinNextAttempt = now.Add(jitter(backoff))
+		return
+	}
+
+	e.joinFailures = 0
+	e.joinDiscovered = discovered
+
+	if len(e.Members()) < e.getExpectedMembers() {
+		e.joinNextAttempt = now.Add(jitter(e.getDnsCheckInterval()))
+		return
+	}
+
+	e.joinNextAttempt = now.Add(jitter(kJoinMaxInterval))
+}
+
+// discoverAndJoin
+//
+// English:
+//
+//  Collects the addresses of all discovery providers and joins the addresses that are not members
+//
+//   Output:
+//     discovered: number of distinct addresses found
+//     joinErr: error of memberlist.Join(), only when no address could be joined
+//     err: error of the last provider, only when no provider found any address
+//
+// Português:
+//
+//  Coleta os endereços de todos os provedores de descoberta e entra nos endereços que não são
+//  membros
+//
+//   Saída:
+//     discovered: número de endereços distintos encontrados
+//     joinErr: erro de memberlist.Join(), apenas quando não foi possível entrar em nenhum endereço
+//     err: erro do último provedor, apenas quando nenhum provedor encontrou endereços
+func (e *Server) discoverAndJoin() (discovered int, joinErr error, err error) {
+	var pass = false
+	var addressList []string
+	var addressListFound = make(map[string]struct{})
+	var providerErr error
+
+	var providerList = make([]Discovery, 0, len(e.discoveryList)+1)
+	if len(e.serviceNameList) != 0 {
+		providerList = append(providerList, &DiscoveryDns{ServiceNames: e.serviceNameList, Family: e.addressFamily})
+	}
+	providerList = append(providerList, e.discoveryList...)
+
+	for _, provider := range providerList {
+		addressList, providerErr = e.bindDiscovery(provider).Discover()
+		if providerErr != nil {
+			e.getLogger().Warn("discovery provider failed", "error", providerErr)
+			err = providerErr
+			continue
+		}
+
+		pass = true
+		for _, address := range addressList {
+			for _, canonical := range e.canonicalJoinAddresses(e.joinAddress(address)) {
+				addressListFound[canonical] = struct{}{}
+			}
+		}
+	}
+
+	if pass == false {
+		e.getLogger().Warn("no instance found by the discovery providers")
+		return
+	}
+
+	err = nil
+	discovered = len(addressListFound)
+
+	// English: the members, this instance included, don't need a new join
+	// Português: os membros, esta instância incluída, não precisam de uma nova entrada
+	for _, member := range e.Members() {
+		delete(addressListFound, net.JoinHostPort(member.Address, strconv.Itoa(int(member.Port))))
+	}
+
+	if len(addressListFound) == 0 {
+		return
+	}
+
+	var addressListToJoin = make([]string, 0, len(addressListFound))
+	for address := range addressListFound {
+		addressListToJoin = append(addressListToJoin, address)
+	}
+
+	var joined int
+	e.getMetrics().IncrCounter([]string{"join", "attempt"}, 1)
+	joined, joinErr = e.memberList.Join(addressListToJoin)
+	if joinErr != nil {
+		e.getMetrics().IncrCounter([]string{"join", "failure"}, 1)
+		e.getLogger().Warn("join failed", "addresses", strings.Join(addressListToJoin, ","), "joined", joined, "error", joinErr)
+	}
+
+	if joined != 0 {
+		joinErr = nil
+	}
+
+	return
+}
+
+// bindDiscovery
+//
+// English:
+//
+//  Returns the provider that uses the configuration of this Server: the go-metrics instance and,
+//  when the provider doesn't define them, the resolver and the preferred address family. The
+//  providers of this package are copied, so a provider can be shared by several Server
+//
+// Português:
+//
+//  Retorna o provedor que usa a configuração deste Server: a instância do go-metrics e, quando o
+//  provedor não os define, o resolvedor e a família de endereços preferida. Os provedores deste
+//  pacote são copiados, assim um provedor pode ser compartilhado por vários Server
+func (e *Server) bindDiscovery(provider Discovery) (bound Discovery) {
+	switch discovery := provider.(type) {
+	case *DiscoveryDns:
+		var copied = *discovery
+		copied.metrics = e.getMetrics()
+		if copied.Resolver == nil {
+			copied.Resolver = e.getResolver()
+		}
+		return &copied
+
+	case *DiscoveryDnsSrv:
+		var copied = *discovery
+		if copied.Resolver == nil {
+			copied.Resolver = e.getResolver()
+		}
+		if copied.Family == AddressFamilyAny {
+			copied.Family = e.addressFamily
+		}
+		return &copied
+	}
+
+	return provider
+}
+
+// joinAddress
+//
+// English:
+//
+//  Completes the address with the sync port when the provider returns only the host, the same
+//  rule used by memberlist.Join(). The port is the one announced to the other members, defined by
+//  SetAdvertiseAddress() or picked by memberlist when Init() received zero. IPv6 addresses are written
+//  between brackets
+//
+// Português:
+//
+//  Completa o endereço com a porta de sincronismo quando o provedor retorna apenas o host, a mesma
+//  regra usada por memberlist.Join(). A porta é a anunciada aos demais membros, definida por
+//  SetAdvertiseAddress() ou escolhida pela memberlist quando Init() recebeu zero. Endereços IPv6 são
+//  escritos entre colchetes
+func (e *Server) joinAddress(address string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	var port = e.syncPort
+	if e.advertisePort != 0 {
+		port = e.advertisePort
+	}
+
+	return net.JoinHostPort(hostOf(address), strconv.Itoa(port))
+}
+
+// canonicalJoinAddresses
+//
+// English:
+//
+//  Returns the address in the same format as the addresses of the members, so the members are not
+//  joined again. A host name is resolved to its IP addresses and an IP address is written in its
+//  canonical form
+//
+//   Input:
+//     address: address in the host:port format
+//
+//   Output:
+//     addresses: canonical addresses in the ip:port format or, when the name can't be resolved, the
+//                address as received, so memberlist.Join() reports the error
+//
+// Português:
+//
+//  Retorna o endereço no mesmo formato dos endereços dos membros, assim os membros não são
+//  adicionados novamente. Um nome de host é resolvido para os seus endereços IP e um endereço IP é
+//  escrito na sua forma canônica
+//
+//   Entrada:
+//     address: endereço no formato host:port
+//
+//   Saída:
+//     addresses: endereços canônicos no formato ip:port ou, quando o nome não pode ser resolvido, o
+//                endereço como recebido, assim memberlist.Join() reporta o erro
+func (e *Server) canonicalJoinAddresses(address string) (addresses []string) {
+	var host, port, err = net.SplitHostPort(address)
+	if err != nil {
+		return []string{address}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{net.JoinHostPort(ip.String(), port)}
+	}
+
+	var ipList []net.IP
+	ipList, err = e.getResolver().LookupIP(context.Background(), host)
+	if err != nil || len(ipList) == 0 {
+		return []string{address}
+	}
+
+	for _, ip := range preferAddressFamily(ipList, e.addressFamily) {
+		addresses = append(addresses, net.JoinHostPort(ip.String(), port))
+	}
+
+	return
+}
+
+// jitter
+//
+// English:
+//
+//  Returns a random duration between half and all of interval, so the instances don't synchronize
+//
+// Português:
+//
+//  Retorna uma duração aleatória entre metade e o total de interval, assim as instâncias não se
+//  sincronizam
+func jitter(interval time.Duration) time.Duration {
+	var half = interval / 2
+	if half <= 0 {
+		return interval
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
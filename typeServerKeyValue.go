@@ -0,0 +1,259 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/memberlist"
+	"time"
+)
+
+const (
+	//kKeyValueTombstoneTTL
+	//
+	// English:
+	//
+	// Time a deleted key is kept as a tombstone. It must be greater than the time needed for the
+	// deletion to reach all members, or the old value may come back from a late member.
+	//
+	// Português:
+	//
+	// Tempo que uma chave apagada é mantida como lápide. Deve ser maior que o tempo necessário para a
+	// remoção chegar a todos os membros, ou o valor antigo pode voltar por um membro atrasado.
+	kKeyValueTombstoneTTL = time.Hour
+)
+
+// Set
+//
+// English:
+//
+//  Sets the value of a key of the replicated map and sends the change to the other members
+//
+//   Input:
+//     key: key of the map
+//     value: value of the key
+//
+//   Note:
+//     * The map is eventually consistent: concurrent changes of the same key are resolved by the
+//       last-writer-wins rule;
+//     * Changes larger than kGossipMessageMaxSize are not sent by gossip and only reach the other
+//       members in the next push/pull synchronism.
+//
+// Português:
+//
+//  Define o valor de uma chave do mapa replicado e envia a alteração para os demais membros
+//
+//   Entrada:
+//     key: chave do mapa
+//     value: valor da chave
+//
+//   Nota:
+//     * O mapa é eventualmente consistente: alterações concorrentes da mesma chave são resolvidas pela
+//       regra do último escritor vence;
+//     * Alterações maiores que kGossipMessageMaxSize não são enviadas por fofoca e só chegam aos
+//       demais membros no próximo sincronismo push/pull.
+func (e *Server) Set(key string, value []byte) {
+	var entry = e.getKeyValueStore().write(key, value, false, e.localNodeName())
+	e.queueBroadcast("kv:"+key, kGossipMessageKeyValue, entry)
+}
+
+// Get
+//
+// English:
+//
+//  Returns the value of a key of the replicated map
+//
+//   Input:
+//     key: key of the map
+//
+//   Output:
+//     value: copy of the value of the key
+//     found: false if the key doesn't exist or was deleted
+//
+// Português:
+//
+//  Retorna o valor de uma chave do mapa replicado
+//
+//   Entrada:
+//     key: chave do mapa
+//
+//   Saída:
+//     value: cópia do valor da chave
+//     found: false se a chave não existe ou foi apagada
+func (e *Server) Get(key string) (value []byte, found bool) {
+	return e.getKeyValueStore().get(key)
+}
+
+// Delete
+//
+// English:
+//
+//  Deletes a key of the replicated map and sends the deletion to the other members
+//
+//   Input:
+//     key: key of the map
+//
+// Português:
+//
+//  Apaga uma chave do mapa replicado e envia a remoção para os demais membros
+//
+//   Entrada:
+//     key: chave do mapa
+func (e *Server) Delete(key string) {
+	var entry = e.getKeyValueStore().write(key, nil, true, e.localNodeName())
+	e.queueBroadcast("kv:"+key, kGossipMessageKeyValue, entry)
+}
+
+// Range
+//
+// English:
+//
+//  Calls function for each key of the replicated map, in key order, until function returns false
+//
+//   Input:
+//     function: receives the key and a copy of the value
+//
+//   Note:
+//     * Range works over a snapshot of the map, so function may call Set() and Delete().
+//
+// Português:
+//
+//  Chama function para cada chave do mapa replicado, na ordem das chaves, até function retornar false
+//
+//   Entrada:
+//     function: recebe a chave e uma cópia do valor
+//
+//   Nota:
+//     * Range trabalha sobre uma cópia do mapa, assim function pode chamar Set() e Delete().
+func (e *Server) Range(function func(key string, value []byte) (continueLoop bool)) {
+	for _, entry := range e.getKeyValueStore().snapshot() {
+		if entry.Deleted == true {
+			continue
+		}
+
+		var value = make([]byte, len(entry.Value))
+		copy(value, entry.Value)
+		if function(entry.Key, value) == false {
+			return
+		}
+	}
+}
+
+// getKeyValueStore
+//
+// English:
+//
+//  Returns the replicated map, creating it on first use
+//
+// Português:
+//
+//  Retorna o mapa replicado, criando-o no primeiro uso
+func (e *Server) getKeyValueStore() (store *keyValueStore) {
+	e.keyValueOnce.Do(func() {
+		e.keyValue = newKeyValueStore()
+	})
+
+	return e.keyValue
+}
+
+// mergeKeyValueMessage
+//
+// English:
+//
+//  Merges a change of the replicated map received by gossip
+//
+// Português:
+//
+//  Mescla uma alteração do mapa replicado recebida por fofoca
+func (e *Server) mergeKeyValueMessage(body []byte) {
+	var entry keyValueEntry
+	var err = json.Unmarshal(body, &entry)
+	if err != nil {
+		e.getLogger().Warn("invalid key value message", "error", err)
+		return
+	}
+
+	// English: memberlist doesn't relay the user broadcasts, so each member relays the entries that
+	// were new to it, and the copies that arrive later are discarded
+	//
+	// Português: a memberlist não retransmite as mensagens do usuário, por isto cada membro retransmite
+	// as entradas que eram novas para ele, e as cópias que chegam depois são descartadas
+	if e.getKeyValueStore().merge(entry) == true {
+		e.queueBroadcast("kv:"+entry.Key, kGossipMessageKeyValue, entry)
+	}
+}
+
+// localNodeName
+//
+// English:
+//
+//  Returns the name of this node in the cluster
+//
+// Português:
+//
+//  Retorna o nome deste node no cluster
+func (e *Server) localNodeName() (name string) {
+	if e.localName != "" {
+		return e.localName
+	}
+
+	return e.nodeName
+}
+
+// newBroadcastQueue
+//
+// English:
+//
+//  Creates the queue of messages sent by gossip
+//
+// Português:
+//
+//  Cria a fila de mensagens enviadas por fofoca
+func (e *Server) newBroadcastQueue(retransmitMult int) (queue *memberlist.TransmitLimitedQueue) {
+	return &memberlist.TransmitLimitedQueue{
+		NumNodes: func() int {
+			if e.memberList == nil {
+				return 1
+			}
+
+			return e.memberList.NumMembers()
+		},
+		RetransmitMult: retransmitMult,
+	}
+}
+
+// queueBroadcast
+//
+// English:
+//
+//  Encodes the message and queues it to be sent by gossip
+//
+//   Input:
+//     name: broadcasts with the same name replace each other in the queue. Empty to never replace
+//     messageType: type of the message, used to route the message on the other members
+//     message: message encoded as JSON
+//
+// Português:
+//
+//  Codifica a mensagem e a coloca na fila para ser enviada por fofoca
+//
+//   Entrada:
+//     name: transmissões com o mesmo nome se substituem na fila. Vazio para nunca substituir
+//     messageType: tipo da mensagem, usado para rotear a mensagem nos demais membros
+//     message: mensagem codificada como JSON
+func (e *Server) queueBroadcast(name string, messageType gossipMessageType, message interface{}) {
+	if e.broadcastQueue == nil {
+		return
+	}
+
+	var data, err = encodeGossipMessage(messageType, message)
+	if err != nil {
+		e.getLogger().Error("gossip message encoding failed", "error", err)
+		return
+	}
+
+	if len(data) > kGossipMessageMaxSize {
+		e.getLogger().Debug("message is larger than the gossip limit, waiting for push/pull", "name", name)
+		return
+	}
+
+	e.broadcastQueue.QueueBroadcast(&gossipBroadcast{name: name, message: data})
+}
@@ -0,0 +1,444 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/memberlist"
+	"net"
+)
+
+// ErrEncryptionDisabled
+//
+// English:
+//
+//  Returned by the keyring functions when SetEncryptionKey() was not called before Init().
+//
+// Português:
+//
+//  Retornado pelas funções de chaveiro quando SetEncryptionKey() não foi chamado antes de Init().
+var ErrEncryptionDisabled = errors.New("gossip encryption is disabled")
+
+// ErrKeyringIncomplete
+//
+// English:
+//
+//  Returned when at least one member failed or did not answer a keyring operation in time.
+//
+// Português:
+//
+//  Retornado quando pelo menos um membro falhou ou não respondeu a uma operação de chaveiro a tempo.
+var ErrKeyringIncomplete = errors.New("keyring operation failed on at least one member")
+
+// keyringOperation
+//
+// English:
+//
+//  Operation sent to all members by InstallKey(), UseKey() and RemoveKey()
+//
+// Português:
+//
+//  Operação enviada a todos os membros por InstallKey(), UseKey() e RemoveKey()
+type keyringOperation string
+
+const (
+	kKeyringInstall keyringOperation = "install"
+	kKeyringUse     keyringOperation = "use"
+	kKeyringRemove  keyringOperation = "remove"
+)
+
+// keyringRequest
+//
+// English:
+//
+//  Message of a keyring operation, sent by memberlist over TCP and encrypted with the current
+//  primary key
+//
+// Português:
+//
+//  Mensagem de uma operação de chaveiro, enviada pela memberlist por TCP e criptografada com a chave
+//  primária atual
+type keyringRequest struct {
+	ID        string           `json:"i"`
+	From      string           `json:"f"`
+	Operation keyringOperation `json:"o"`
+	Key       []byte           `json:"k"`
+}
+
+// keyringResponse
+//
+// English:
+//
+//  Answer of a member to a keyring operation
+//
+// Português:
+//
+//  Resposta de um membro a uma operação de chaveiro
+type keyringResponse struct {
+	ID    string `json:"i"`
+	Node  string `json:"n"`
+	Error string `json:"e,omitempty"`
+}
+
+// SetEncryptionKey
+//
+// English:
+//
+//  Enables the encryption of all memberlist traffic
+//
+//   Input:
+//     primaryKey: key used to encrypt the messages, with 16, 24 or 32 bytes for AES-128, AES-192 or
+//                 AES-256
+//     secondaryKeys: other keys accepted to decrypt the messages, useful while a rotation is in
+//                    progress
+//
+//   Output:
+//     err: standard error object
+//
+//   Note:
+//     * Must be called before Init() with the same primary key on all members;
+//     * Use InstallKey(), UseKey() and RemoveKey() to rotate the keys of a running cluster.
+//
+// Português:
+//
+//  Habilita a criptografia de todo o tráfego da memberlist
+//
+//   Entrada:
+//     primaryKey: chave usada para criptografar as mensagens, com 16, 24 ou 32 bytes para AES-128,
+//                 AES-192 ou AES-256
+//     secondaryKeys: outras chaves aceitas para descriptografar as mensagens, úteis enquanto uma
+//                    rotação está em andamento
+//
+//   Saída:
+//     err: objeto de erro padrão
+//
+//   Nota:
+//     * Deve ser chamado antes de Init() com a mesma chave primária em todos os membros;
+//     * Use InstallKey(), UseKey() e RemoveKey() para trocar as chaves de um cluster em execução.
+func (e *Server) SetEncryptionKey(primaryKey []byte, secondaryKeys ...[]byte) (err error) {
+	var keys = append([][]byte{primaryKey}, secondaryKeys...)
+	e.keyring, err = memberlist.NewKeyring(keys, primaryKey)
+	return
+}
+
+// InstallKey
+//
+// English:
+//
+//  First step of a key rotation: installs a new key on all members, without using it to encrypt
+//
+//   Input:
+//     ctx: limits the time spent waiting for the answers
+//     key: new key, with 16, 24 or 32 bytes
+//
+//   Output:
+//     results: key: node name / value: nil on success or the error of the node
+//     err: ErrKeyringIncomplete if any node failed or didn't answer, or standard error object
+//
+// Português:
+//
+//  Primeiro passo de uma troca de chave: instala uma nova chave em todos os membros, sem usá-la para
+//  criptografar
+//
+//   Entrada:
+//     ctx: limita o tempo de espera pelas respostas
+//     key: nova chave, com 16, 24 ou 32 bytes
+//
+//   Saída:
+//     results: chave: nome do node / valor: nil em caso de sucesso ou o erro do node
+//     err: ErrKeyringIncomplete se algum node falhou ou não respondeu, ou objeto de erro padrão
+func (e *Server) InstallKey(ctx context.Context, key []byte) (results map[string]error, err error) {
+	return e.keyringBroadcast(ctx, kKeyringInstall, key)
+}
+
+// UseKey
+//
+// English:
+//
+//  Second step of a key rotation: makes an installed key the primary key on all members
+//
+//   Input:
+//     ctx: limits the time spent waiting for the answers
+//     key: key installed by InstallKey()
+//
+//   Output:
+//     results: key: node name / value: nil on success or the error of the node
+//     err: ErrKeyringIncomplete if any node failed or didn't answer, or standard error object
+//
+// Português:
+//
+//  Segundo passo de uma troca de chave: torna uma chave instalada a chave primária em todos os
+//  membros
+//
+//   Entrada:
+//     ctx: limita o tempo de espera pelas respostas
+//     key: chave instalada por InstallKey()
+//
+//   Saída:
+//     results: chave: nome do node / valor: nil em caso de sucesso ou o erro do node
+//     err: ErrKeyringIncomplete se algum node falhou ou não respondeu, ou objeto de erro padrão
+func (e *Server) UseKey(ctx context.Context, key []byte) (results map[string]error, err error) {
+	return e.keyringBroadcast(ctx, kKeyringUse, key)
+}
+
+// RemoveKey
+//
+// English:
+//
+//  Last step of a key rotation: removes the old key from all members
+//
+//   Input:
+//     ctx: limits the time spent waiting for the answers
+//     key: old key. The primary key can't be removed
+//
+//   Output:
+//     results: key: node name / value: nil on success or the error of the node
+//     err: ErrKeyringIncomplete if any node failed or didn't answer, or standard error object
+//
+// Português:
+//
+//  Último passo de uma troca de chave: remove a chave antiga de todos os membros
+//
+//   Entrada:
+//     ctx: limita o tempo de espera pelas respostas
+//     key: chave antiga. A chave primária não pode ser removida
+//
+//   Saída:
+//     results: chave: nome do node / valor: nil em caso de sucesso ou o erro do node
+//     err: ErrKeyringIncomplete se algum node falhou ou não respondeu, ou objeto de erro padrão
+func (e *Server) RemoveKey(ctx context.Context, key []byte) (results map[string]error, err error) {
+	return e.keyringBroadcast(ctx, kKeyringRemove, key)
+}
+
+// keyringBroadcast
+//
+// English:
+//
+//  Applies the operation on this node and sends it to all other members, waiting for the answers.
+//  When the operation fails on this node, it is not sent and the error of this node is returned
+//
+// Português:
+//
+//  Aplica a operação neste node e a envia para todos os demais membros, esperando pelas respostas.
+//  Quando a operação falha neste node, ela não é enviada e o erro deste node é retornado
+func (e *Server) keyringBroadcast(ctx context.Context, operation keyringOperation, key []byte) (results map[string]error, err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	if e.keyring == nil {
+		err = ErrEncryptionDisabled
+		return
+	}
+
+	var request = keyringRequest{
+		ID:        newMessageID(),
+		From:      e.localNodeName(),
+		Operation: operation,
+		Key:       key,
+	}
+
+	var data []byte
+	data, err = encodeGossipMessage(kGossipMessageKeyringRequest, request)
+	if err != nil {
+		return
+	}
+
+	results = make(map[string]error)
+	results[request.From] = e.applyKeyringOperation(operation, key)
+	if results[request.From] != nil {
+		err = results[request.From]
+		return
+	}
+
+	// English: one answer per member fits in the channel, so no answer is discarded while the requests
+	// are sent
+	// Português: uma resposta por membro cabe no canal, assim nenhuma resposta é descartada enquanto as
+	// requisições são enviadas
+	var members = e.Members()
+	var answers = make(chan keyringResponse, len(members))
+	e.keyringMutex.Lock()
+	if e.keyringPending == nil {
+		e.keyringPending = make(map[string]chan keyringResponse)
+	}
+	e.keyringPending[request.ID] = answers
+	e.keyringMutex.Unlock()
+
+	defer func() {
+		e.keyringMutex.Lock()
+		delete(e.keyringPending, request.ID)
+		e.keyringMutex.Unlock()
+	}()
+
+	var waiting = make(map[string]bool)
+	for _, member := range members {
+		if member.Name == request.From {
+			continue
+		}
+
+		var node = &memberlist.Node{Name: member.Name, Addr: net.ParseIP(member.Address), Port: uint16(member.Port)}
+		var errSend = e.memberList.SendReliable(node, data)
+		if errSend != nil {
+			results[member.Name] = errSend
+			continue
+		}
+
+		waiting[member.Name] = true
+	}
+
+	for len(waiting) != 0 {
+		select {
+		case answer := <-answers:
+			if waiting[answer.Node] == false {
+				continue
+			}
+
+			delete(waiting, answer.Node)
+			results[answer.Node] = nil
+			if answer.Error != "" {
+				results[answer.Node] = errors.New(answer.Error)
+			}
+
+		case <-ctx.Done():
+			for node := range waiting {
+				results[node] = ctx.Err()
+			}
+			waiting = nil
+		}
+	}
+
+	for _, errNode := range results {
+		if errNode != nil {
+			err = ErrKeyringIncomplete
+		}
+	}
+
+	return
+}
+
+// applyKeyringOperation
+//
+// English:
+//
+//  Applies a keyring operation on this node
+//
+// Português:
+//
+//  Aplica uma operação de chaveiro neste node
+func (e *Server) applyKeyringOperation(operation keyringOperation, key []byte) (err error) {
+	if e.keyring == nil {
+		return ErrEncryptionDisabled
+	}
+
+	switch operation {
+	case kKeyringInstall:
+		return e.keyring.AddKey(key)
+	case kKeyringUse:
+		return e.keyring.UseKey(key)
+	case kKeyringRemove:
+		return e.keyring.RemoveKey(key)
+	}
+
+	return errors.New("unknown keyring operation: " + string(operation))
+}
+
+// handleKeyringRequest
+//
+// English:
+//
+//  Applies a keyring operation received from another member and sends the answer back
+//
+// Português:
+//
+//  Aplica uma operação de chaveiro recebida de outro membro e envia a resposta de volta
+func (e *Server) handleKeyringRequest(body []byte) {
+	var request keyringRequest
+	var err = json.Unmarshal(body, &request)
+	if err != nil {
+		e.getLogger().Warn("invalid keyring request", "error", err)
+		return
+	}
+
+	var response = keyringResponse{
+		ID:   request.ID,
+		Node: e.localNodeName(),
+	}
+
+	err = e.applyKeyringOperation(request.Operation, request.Key)
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	var member, found = e.getMember(request.From)
+	if found == false {
+		e.getLogger().Warn("keyring request from unknown node", "node", request.From)
+		return
+	}
+
+	var data []byte
+	data, err = encodeGossipMessage(kGossipMessageKeyringResponse, response)
+	if err != nil {
+		e.getLogger().Error("gossip message encoding failed", "error", err)
+		return
+	}
+
+	// English: NotifyMsg must not block, so the answer is sent by another goroutine
+	// Português: NotifyMsg não pode bloquear, por isto a resposta é enviada por outra goroutine
+	go func() {
+		var node = &memberlist.Node{Name: member.Name, Addr: net.ParseIP(member.Address), Port: uint16(member.Port)}
+		var err = e.memberList.SendReliable(node, data)
+		if err != nil {
+			e.getLogger().Warn("keyring message not sent", "error", err)
+		}
+	}()
+}
+
+// handleKeyringResponse
+//
+// English:
+//
+//  Delivers the answer of a member to the keyring operation waiting for it
+//
+// Português:
+//
+//  Entrega a resposta de um membro para a operação de chaveiro que a espera
+func (e *Server) handleKeyringResponse(body []byte) {
+	var response keyringResponse
+	var err = json.Unmarshal(body, &response)
+	if err != nil {
+		e.getLogger().Warn("invalid keyring response", "error", err)
+		return
+	}
+
+	e.keyringMutex.Lock()
+	defer e.keyringMutex.Unlock()
+
+	var answers, found = e.keyringPending[response.ID]
+	if found == false {
+		return
+	}
+
+	select {
+	case answers <- response:
+	default:
+		e.getLogger().Warn("keyring response discarded", "node", response.Node)
+	}
+}
+
+// newMessageID
+//
+// English:
+//
+//  Returns a random identifier for a message
+//
+// Português:
+//
+//  Retorna um identificador aleatório para uma mensagem
+func newMessageID() (id string) {
+	var buffer = make([]byte, 16)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
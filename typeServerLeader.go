@@ -0,0 +1,232 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"time"
+)
+
+const (
+	//kLeaderGracePeriod
+	//
+	// English:
+	//
+	// Time a new leader candidate must remain the candidate before the leadership changes, so a
+	// single missed probe doesn't make the leadership flap.
+	//
+	// Português:
+	//
+	// Tempo que um novo candidato a líder deve continuar candidato antes da liderança mudar, assim uma
+	// única sondagem perdida não faz a liderança oscilar.
+	kLeaderGracePeriod = time.Second * 3
+)
+
+// LeaderChangeHandler
+//
+// English:
+//
+//  Function called when the leader of the cluster changes
+//
+//   Input:
+//     leader: name of the new leader. Empty when there is no ready member
+//     isLeader: true if this node is the new leader
+//
+// Português:
+//
+//  Função chamada quando o líder do cluster muda
+//
+//   Entrada:
+//     leader: nome do novo líder. Vazio quando não há membro pronto
+//     isLeader: true se este node é o novo líder
+type LeaderChangeHandler func(leader string, isLeader bool)
+
+// SetLeaderPriority
+//
+// English:
+//
+//  Defines the priority of this node in the leader election. The ready member with the highest
+//  priority is the leader; ties are broken by the smallest node name
+//
+//   Input:
+//     priority: priority of the node, by default, zero
+//
+//   Note:
+//     * Can be called at any time, the new priority is sent to the other members by the metadata.
+//
+// Português:
+//
+//  Define a prioridade deste node na eleição de líder. O membro pronto com a maior prioridade é o
+//  líder; empates são resolvidos pelo menor nome de node
+//
+//   Entrada:
+//     priority: prioridade do node, por padrão, zero
+//
+//   Nota:
+//     * Pode ser chamado a qualquer momento, a nova prioridade é enviada aos demais membros pelos
+//       metadados.
+func (e *Server) SetLeaderPriority(priority int) {
+	e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.LeaderPriority = priority
+	})
+
+	if e.memberList == nil {
+		return
+	}
+
+	var err = e.memberList.UpdateNode(e.getDnsCheckInterval())
+	if err != nil {
+		e.getLogger().Warn("node metadata update failed", "error", err)
+	}
+}
+
+// SetLeaderGracePeriod
+//
+// English:
+//
+//  Defines the time a new candidate must remain the candidate before the leadership changes
+//
+//   Input:
+//     gracePeriod: grace period, by default, kLeaderGracePeriod
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o tempo que um novo candidato deve continuar candidato antes da liderança mudar
+//
+//   Entrada:
+//     gracePeriod: período de carência, por padrão, kLeaderGracePeriod
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetLeaderGracePeriod(gracePeriod time.Duration) {
+	e.leaderGracePeriod = gracePeriod
+}
+
+// SetLeaderChangeHandler
+//
+// English:
+//
+//  Defines the function called when the leader of the cluster changes
+//
+//   Input:
+//     handler: function called from the synchronism loop, it must not block
+//
+// Português:
+//
+//  Define a função chamada quando o líder do cluster muda
+//
+//   Entrada:
+//     handler: função chamada pelo laço de sincronismo, ela não deve bloquear
+func (e *Server) SetLeaderChangeHandler(handler LeaderChangeHandler) {
+	e.leaderMutex.Lock()
+	defer e.leaderMutex.Unlock()
+
+	e.leaderChangeHandler = handler
+}
+
+// IsLeader
+//
+// English:
+//
+//  Returns true if this node is the leader of the cluster
+//
+// Português:
+//
+//  Retorna true se este node é o líder do cluster
+func (e *Server) IsLeader() (isLeader bool) {
+	var leader = e.Leader()
+	return leader != "" && leader == e.localNodeName()
+}
+
+// Leader
+//
+// English:
+//
+//  Returns the name of the leader of the cluster, or an empty string while there is no leader
+//
+// Português:
+//
+//  Retorna o nome do líder do cluster, ou uma string vazia enquanto não há líder
+func (e *Server) Leader() (leader string) {
+	e.leaderMutex.Lock()
+	defer e.leaderMutex.Unlock()
+
+	return e.leaderName
+}
+
+// electLeader
+//
+// English:
+//
+//  Returns the ready member with the highest priority, breaking ties by the smallest name. All
+//  members with the same view of the cluster elect the same leader
+//
+// Português:
+//
+//  Retorna o membro pronto com a maior prioridade, desempatando pelo menor nome. Todos os membros com
+//  a mesma visão do cluster elegem o mesmo líder
+func (e *Server) electLeader() (leader string) {
+	var leaderPriority int
+	for _, member := range e.Members() {
+		var meta = decodeNodeMeta(member.Meta)
+		if meta.Ready == false || meta.Leaving == true {
+			continue
+		}
+
+		if leader == "" ||
+			meta.LeaderPriority > leaderPriority ||
+			(meta.LeaderPriority == leaderPriority && member.Name < leader) {
+			leader = member.Name
+			leaderPriority = meta.LeaderPriority
+		}
+	}
+
+	return
+}
+
+// updateLeader
+//
+// English:
+//
+//  Runs the election and changes the leader after the candidate has been the same for the whole
+//  grace period
+//
+// Português:
+//
+//  Executa a eleição e muda o líder depois que o candidato foi o mesmo durante todo o período de
+//  carência
+func (e *Server) updateLeader() {
+	var candidate = e.electLeader()
+	var now = time.Now()
+
+	var gracePeriod = e.leaderGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = kLeaderGracePeriod
+	}
+
+	e.leaderMutex.Lock()
+	if candidate == e.leaderName {
+		e.leaderCandidate = candidate
+		e.leaderMutex.Unlock()
+		return
+	}
+
+	if candidate != e.leaderCandidate || e.leaderCandidateSince.IsZero() == true {
+		e.leaderCandidate = candidate
+		e.leaderCandidateSince = now
+	}
+
+	if now.Sub(e.leaderCandidateSince) < gracePeriod {
+		e.leaderMutex.Unlock()
+		return
+	}
+
+	e.leaderName = candidate
+	var handler = e.leaderChangeHandler
+	e.leaderMutex.Unlock()
+
+	e.getLogger().Info("leader changed", "leader", candidate)
+	if handler != nil {
+		handler(candidate, candidate != "" && candidate == e.localNodeName())
+	}
+}
@@ -0,0 +1,232 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"github.com/armon/go-metrics"
+	"net"
+	"net/http"
+)
+
+// SetMetricsAddress
+//
+// English:
+//
+//  Defines the address of the HTTP server that exposes the metrics in the Prometheus text format
+//  on the /metrics path
+//
+//   Input:
+//     address: address in the host:port format, for example ":9100". Empty, the default value,
+//              doesn't start the HTTP server
+//
+//   Note:
+//     * Must be called before Init();
+//     * MetricsHandler() can be used to expose the metrics in an HTTP server of the application.
+//
+// Português:
+//
+//  Define o endereço do servidor HTTP que expõe as métricas no formato texto do Prometheus no
+//  caminho /metrics
+//
+//   Entrada:
+//     address: endereço no formato host:porta, por exemplo ":9100". Vazio, o valor padrão, não
+//              inicia o servidor HTTP
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * MetricsHandler() pode ser usado para expor as métricas em um servidor HTTP da aplicação.
+func (e *Server) SetMetricsAddress(address string) {
+	e.metricsAddress = address
+}
+
+// MetricsHandler
+//
+// English:
+//
+//  Returns the http.Handler that writes the metrics in the Prometheus text format.
+//
+//   Metrics:
+//     members{node,state}: number of members by state, alive, leaving and ready;
+//     node_health_score{node}: memberlist health score, zero is healthy;
+//     node_event{type}: number of join, leave, failure and address change events;
+//     discovery_dns_lookup{service}: DNS lookup time in milliseconds;
+//     discovery_dns_failure{service}: number of DNS lookup failures;
+//     join_attempt, join_failure: number of join attempts and failures;
+//     memberlist_*: gossip, probe and message metrics of the memberlist, only after
+//                   EnableMemberlistMetrics().
+//
+//   Note:
+//     * The metrics of the Server don't use the global go-metrics instance of the application.
+//
+// Português:
+//
+//  Retorna o http.Handler que escreve as métricas no formato texto do Prometheus.
+//
+//   Métricas:
+//     members{node,state}: número de membros por estado, alive, leaving e ready;
+//     node_health_score{node}: pontuação de saúde da memberlist, zero é saudável;
+//     node_event{type}: número de eventos de entrada, saída, falha e mudança de endereço;
+//     discovery_dns_lookup{service}: tempo da consulta DNS em milissegundos;
+//     discovery_dns_failure{service}: número de falhas de consulta DNS;
+//     join_attempt, join_failure: número de tentativas e de falhas de entrada no cluster;
+//     memberlist_*: métricas de gossip, sondagem e mensagens da memberlist, apenas depois de
+//                   EnableMemberlistMetrics().
+//
+//   Nota:
+//     * As métricas do Server não usam a instância global do go-metrics da aplicação.
+func (e *Server) MetricsHandler() (handler http.Handler) {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		e.updateMetrics()
+
+		writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		var err = e.getMetricsSink().write(writer)
+		if err != nil {
+			e.getLogger().Debug("metrics not written", "error", err)
+		}
+	})
+}
+
+// updateMetrics
+//
+// English:
+//
+//  Updates the gauges calculated at the time of the scrape
+//
+// Português:
+//
+//  Atualiza os medidores calculados no momento da coleta
+func (e *Server) updateMetrics() {
+	if e.memberList == nil {
+		return
+	}
+
+	var alive, leaving, ready float32
+	for _, member := range e.Members() {
+		switch member.State {
+		case MemberAlive:
+			alive += 1
+		case MemberLeaving:
+			leaving += 1
+		}
+
+		if member.Ready == true {
+			ready += 1
+		}
+	}
+
+	var writer = e.getMetrics()
+	var node = metrics.Label{Name: "node", Value: e.localNodeName()}
+	writer.SetGaugeWithLabels([]string{"members"}, alive, []metrics.Label{node, {Name: "state", Value: MemberAlive.String()}})
+	writer.SetGaugeWithLabels([]string{"members"}, leaving, []metrics.Label{node, {Name: "state", Value: MemberLeaving.String()}})
+	writer.SetGaugeWithLabels([]string{"members"}, ready, []metrics.Label{node, {Name: "state", Value: "ready"}})
+	writer.SetGaugeWithLabels([]string{"node", "health", "score"}, float32(e.memberList.GetHealthScore()), []metrics.Label{node})
+}
+
+// getMetricsSink
+//
+// English:
+//
+//  Returns the sink of this Server, created on the first use
+//
+// Português:
+//
+//  Retorna o coletor deste Server, criado no primeiro uso
+func (e *Server) getMetricsSink() (sink *prometheusSink) {
+	e.metricsOnce.Do(func() {
+		e.metricsSink = newPrometheusSink()
+		e.metricsWriter, _ = metrics.New(newMetricsConfig(), e.metricsSink)
+	})
+
+	return e.metricsSink
+}
+
+// getMetrics
+//
+// English:
+//
+//  Returns the go-metrics instance of this Server, which writes only to the sink of this Server
+//
+// Português:
+//
+//  Retorna a instância do go-metrics deste Server, que escreve apenas no coletor deste Server
+func (e *Server) getMetrics() (writer *metrics.Metrics) {
+	e.getMetricsSink()
+	return e.metricsWriter
+}
+
+// metricsListen
+//
+// English:
+//
+//  Receives the memberlist metrics, see EnableMemberlistMetrics(), and, when SetMetricsAddress() was
+//  called, opens the port of the HTTP server
+//
+// Português:
+//
+//  Recebe as métricas da memberlist, veja EnableMemberlistMetrics(), e, quando SetMetricsAddress()
+//  foi chamado, abre a porta do servidor HTTP
+func (e *Server) metricsListen() (err error) {
+	memberlistMetrics.add(e.getMetricsSink())
+
+	if e.metricsAddress == "" {
+		return
+	}
+
+	e.metricsListener, err = net.Listen("tcp", e.metricsAddress)
+	if err != nil {
+		e.getLogger().Error("metrics address listen failed", "address", e.metricsAddress, "error", err)
+		memberlistMetrics.remove(e.getMetricsSink())
+		return
+	}
+
+	var mux = http.NewServeMux()
+	mux.Handle("/metrics", e.MetricsHandler())
+	e.metricsServer = &http.Server{Handler: mux}
+
+	return
+}
+
+// metricsServe
+//
+// English:
+//
+//  Serves the HTTP requests until metricsStop()
+//
+// Português:
+//
+//  Atende as requisições HTTP até metricsStop()
+func (e *Server) metricsServe() {
+	if e.metricsServer == nil {
+		return
+	}
+
+	go func(e *Server) {
+		var err = e.metricsServer.Serve(e.metricsListener)
+		if err != nil && err != http.ErrServerClosed {
+			e.getLogger().Error("metrics server stopped", "error", err)
+		}
+	}(e)
+}
+
+// metricsStop
+//
+// English:
+//
+//  Stops receiving the memberlist metrics and stops the HTTP server, waiting for the requests in
+//  progress until ctx is done
+//
+// Português:
+//
+//  Para de receber as métricas da memberlist e para o servidor HTTP, esperando as requisições em
+//  andamento até ctx terminar
+func (e *Server) metricsStop(ctx context.Context) {
+	memberlistMetrics.remove(e.getMetricsSink())
+
+	if e.metricsServer == nil {
+		return
+	}
+
+	var err = e.metricsServer.Shutdown(ctx)
+	if err != nil {
+		_ = e.metricsServer.Close()
+	}
+}
@@ -0,0 +1,220 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	//kNodeReapTTL
+	//
+	// English:
+	//
+	// Default time a node that left the cluster is kept as a tombstone in the node history.
+	//
+	// Português:
+	//
+	// Tempo padrão em que um node que saiu do cluster é mantido como lápide no histórico de nodes.
+	kNodeReapTTL = 24 * time.Hour
+)
+
+// ErrNodeIsMember
+//
+// English: the node is still a member of the cluster and can't be marked as gone
+//
+// Português: o node ainda é membro do cluster e não pode ser marcado como removido
+var ErrNodeIsMember = errors.New("node is still a member of the cluster")
+
+// SetNodeReapTTL
+//
+// English:
+//
+//  Defines the time a node that left the cluster is kept as a tombstone in the node history
+//
+//   Input:
+//     ttl: time to keep the tombstones, by default, 24 hours
+//
+// Português:
+//
+//  Define o tempo em que um node que saiu do cluster é mantido como lápide no histórico de nodes
+//
+//   Entrada:
+//     ttl: tempo para manter as lápides, por padrão, 24 horas
+func (e *Server) SetNodeReapTTL(ttl time.Duration) {
+	e.nodeReapTTL = ttl
+}
+
+// SetNodeHistoryPath
+//
+// English:
+//
+//  Defines the file where the node history is persisted, so a restarted instance remembers the
+//  recent history
+//
+//   Input:
+//     path: path of the JSON file. Empty, the default value, keeps the history only in memory
+//
+//   Note:
+//     * Must be called before Init();
+//     * The file is read by Init() and written by the sync loop, when the history changes, and by
+//       Shutdown().
+//
+// Português:
+//
+//  Define o arquivo onde o histórico de nodes é persistido, assim uma instância reiniciada se lembra
+//  do histórico recente
+//
+//   Entrada:
+//     path: caminho do arquivo JSON. Vazio, o valor padrão, mantém o histórico apenas em memória
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * O arquivo é lido por Init() e escrito pelo ciclo de sincronismo, quando o histórico muda, e
+//       por Shutdown().
+func (e *Server) SetNodeHistoryPath(path string) {
+	e.nodeHistoryPath = path
+}
+
+// NodeHistory
+//
+// English:
+//
+//  Returns the members and the tombstones of the nodes that left the cluster, ordered by name
+//
+// Português:
+//
+//  Retorna os membros e as lápides dos nodes que saíram do cluster, ordenados pelo nome
+func (e *Server) NodeHistory() (records []NodeRecord) {
+	if e.nodeHistory == nil {
+		return make([]NodeRecord, 0)
+	}
+
+	return e.nodeHistory.snapshot()
+}
+
+// MarkNodeGone
+//
+// English:
+//
+//  Marks a node that left the cluster as permanently gone, for example, after a scale down
+//
+//   Input:
+//     name: name of the node
+//
+//   Output:
+//     err: ErrServerNotInitialized, ErrNodeNotFound or ErrNodeIsMember
+//
+//   Note:
+//     * The tombstone is kept until the reap TTL expires;
+//     * If a node with the same name joins again, it becomes alive again.
+//
+// Português:
+//
+//  Marca um node que saiu do cluster como removido em definitivo, por exemplo, após uma redução de
+//  escala
+//
+//   Entrada:
+//     name: nome do node
+//
+//   Saída:
+//     err: ErrServerNotInitialized, ErrNodeNotFound ou ErrNodeIsMember
+//
+//   Nota:
+//     * A lápide é mantida até o TTL de remoção expirar;
+//     * Se um node com o mesmo nome entrar novamente, ele volta a ficar vivo.
+func (e *Server) MarkNodeGone(name string) (err error) {
+	if e.nodeHistory == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	var record, found = e.nodeHistory.get(name)
+	if found == false {
+		err = ErrNodeNotFound
+		return
+	}
+
+	if record.Status == NodeStatusAlive {
+		err = ErrNodeIsMember
+		return
+	}
+
+	e.nodeHistory.down(name, NodeStatusGone, time.Now())
+	return
+}
+
+// getNodeReapTTL
+//
+// English:
+//
+//  Returns the reap TTL, applying the default value
+//
+// Português:
+//
+//  Retorna o TTL de remoção, aplicando o valor padrão
+func (e *Server) getNodeReapTTL() (ttl time.Duration) {
+	if e.nodeReapTTL <= 0 {
+		return kNodeReapTTL
+	}
+
+	return e.nodeReapTTL
+}
+
+// loadNodeHistory
+//
+// English:
+//
+//  Creates the node history and reads the file defined by SetNodeHistoryPath()
+//
+// Português:
+//
+//  Cria o histórico de nodes e lê o arquivo definido por SetNodeHistoryPath()
+func (e *Server) loadNodeHistory() {
+	e.nodeHistory = newNodeHistory()
+	if e.nodeHistoryPath == "" {
+		return
+	}
+
+	var err = e.nodeHistory.load(e.nodeHistoryPath)
+	if err != nil {
+		e.getLogger().Warn("node history not loaded", "path", e.nodeHistoryPath, "error", err)
+	}
+}
+
+// updateNodeHistory
+//
+// English:
+//
+//  Removes the expired tombstones and writes the history when it changed
+//
+// Português:
+//
+//  Remove as lápides expiradas e escreve o histórico quando ele mudou
+func (e *Server) updateNodeHistory() {
+	var removed = e.nodeHistory.reap(e.getNodeReapTTL(), time.Now())
+	if len(removed) != 0 {
+		e.getLogger().Debug("node tombstones reaped", "nodes", len(removed))
+	}
+
+	e.saveNodeHistory()
+}
+
+// saveNodeHistory
+//
+// English:
+//
+//  Writes the history in the file defined by SetNodeHistoryPath()
+//
+// Português:
+//
+//  Escreve o histórico no arquivo definido por SetNodeHistoryPath()
+func (e *Server) saveNodeHistory() {
+	if e.nodeHistoryPath == "" {
+		return
+	}
+
+	var err = e.nodeHistory.save(e.nodeHistoryPath, time.Now())
+	if err != nil {
+		e.getLogger().Warn("node history not saved", "path", e.nodeHistoryPath, "error", err)
+	}
+}
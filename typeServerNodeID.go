@@ -0,0 +1,181 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetNodeIDPath
+//
+// English:
+//
+//  Defines the file that keeps the logical ID of this node between restarts
+//
+//   Input:
+//     path: path of the file. When the file doesn't exist, Init() generates a new ID and creates it
+//
+//   Note:
+//     * Must be called before Init();
+//     * In a container, the path must be in a volume that survives the restart;
+//     * Without SetNodeIDPath() and SetNodeIDEnv(), the ID is derived from the node name, see
+//       SetNodeName(), so it only survives the restart while the name doesn't change.
+//
+// Português:
+//
+//  Define o arquivo que guarda o ID lógico deste node entre reinícios
+//
+//   Entrada:
+//     path: caminho do arquivo. Quando o arquivo não existe, Init() gera um novo ID e o cria
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * Em um container, o caminho deve estar em um volume que sobrevive ao reinício;
+//     * Sem SetNodeIDPath() e SetNodeIDEnv(), o ID é derivado do nome do node, veja SetNodeName(),
+//       assim ele só sobrevive ao reinício enquanto o nome não muda.
+func (e *Server) SetNodeIDPath(path string) {
+	e.nodeIDPath = path
+}
+
+// SetNodeIDEnv
+//
+// English:
+//
+//  Defines the environment variable that contains the logical ID of this node
+//
+//   Input:
+//     name: name of the environment variable, for example "NODE_ID"
+//
+//   Note:
+//     * Must be called before Init();
+//     * When the variable is defined and not empty, it has priority over SetNodeIDPath().
+//
+// Português:
+//
+//  Define a variável de ambiente que contém o ID lógico deste node
+//
+//   Entrada:
+//     name: nome da variável de ambiente, por exemplo "NODE_ID"
+//
+//   Nota:
+//     * Deve ser chamado antes de Init();
+//     * Quando a variável está definida e não é vazia, ela tem prioridade sobre SetNodeIDPath().
+func (e *Server) SetNodeIDEnv(name string) {
+	e.nodeIDEnv = name
+}
+
+// NodeID
+//
+// English:
+//
+//  Returns the logical ID of this node, carried in the node metadata. Empty before Init()
+//
+// Português:
+//
+//  Retorna o ID lógico deste node, levado nos metadados do node. Vazio antes de Init()
+func (e *Server) NodeID() (id string) {
+	return e.getNodeMeta().ID
+}
+
+// loadNodeID
+//
+// English:
+//
+//  Defines the ID of the node from the environment variable, from the file or, in the last case,
+//  from the node name, and publishes it in the node metadata
+//
+//   Output:
+//     err: error reading or creating the file defined by SetNodeIDPath() or ErrNodeMetaTooLarge
+//
+// Português:
+//
+//  Define o ID do node pela variável de ambiente, pelo arquivo ou, em último caso, pelo nome do
+//  node, e o publica nos metadados do node
+//
+//   Saída:
+//     err: erro ao ler ou criar o arquivo definido por SetNodeIDPath() ou ErrNodeMetaTooLarge
+func (e *Server) loadNodeID() (err error) {
+	var id string
+
+	if e.nodeIDEnv != "" {
+		id = strings.TrimSpace(os.Getenv(e.nodeIDEnv))
+	}
+
+	if id == "" && e.nodeIDPath != "" {
+		id, err = e.readNodeID()
+		if err != nil {
+			return
+		}
+	}
+
+	if id == "" {
+		id = e.defaultNodeID()
+	}
+
+	err = e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.ID = id
+	})
+
+	return
+}
+
+// defaultNodeID
+//
+// English:
+//
+//  Returns the ID derived from the node name, defined by SetNodeName() or, by default, the host name
+//  as in memberlist. Without a name, returns a new random ID
+//
+// Português:
+//
+//  Retorna o ID derivado do nome do node, definido por SetNodeName() ou, por padrão, o nome do host
+//  como na memberlist. Sem um nome, retorna um novo ID aleatório
+func (e *Server) defaultNodeID() (id string) {
+	var name = e.nodeName
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	if name == "" {
+		return newMessageID()
+	}
+
+	var sum = sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:16])
+}
+
+// readNodeID
+//
+// English:
+//
+//  Reads the ID of the file defined by SetNodeIDPath(), creating the file with a new ID when it
+//  doesn't exist
+//
+// Português:
+//
+//  Lê o ID do arquivo definido por SetNodeIDPath(), criando o arquivo com um novo ID quando ele não
+//  existe
+func (e *Server) readNodeID() (id string, err error) {
+	var data []byte
+	data, err = os.ReadFile(e.nodeIDPath)
+	if err == nil {
+		id = strings.TrimSpace(string(data))
+		if id != "" {
+			return
+		}
+	} else if os.IsNotExist(err) == false {
+		return
+	}
+
+	id = newMessageID()
+
+	err = os.MkdirAll(filepath.Dir(e.nodeIDPath), 0755)
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(e.nodeIDPath, []byte(id+"\n"), 0644)
+	return
+}
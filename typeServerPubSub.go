@@ -0,0 +1,398 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"errors"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"sync"
+)
+
+// ErrInvalidTopic
+//
+// English:
+//
+//  Returned when the topic is empty.
+//
+// Português:
+//
+//  Retornado quando o tópico é vazio.
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// ErrPubSubNotSubscribed
+//
+// English:
+//
+//  Returned by the publisher when the stream doesn't start with the subscription of the node.
+//
+// Português:
+//
+//  Retornado pelo publicador quando o stream não começa com a inscrição do node.
+var ErrPubSubNotSubscribed = errors.New("the stream must start with the subscription of the node")
+
+// TopicHandler
+//
+// English:
+//
+//  Function that receives the messages of a topic published by the members of the cluster
+//
+//   Input:
+//     topic: topic of the message
+//     from: name of the node that published the message
+//     payload: content of the message
+//
+//   Note:
+//     * The messages of a publisher are delivered one at a time and in the order they were
+//       published, so a slow handler delays the next messages of that publisher.
+//
+// Português:
+//
+//  Função que recebe as mensagens de um tópico publicadas pelos membros do cluster
+//
+//   Entrada:
+//     topic: tópico da mensagem
+//     from: nome do node que publicou a mensagem
+//     payload: conteúdo da mensagem
+//
+//   Nota:
+//     * As mensagens de um publicador são entregues uma de cada vez e na ordem em que foram
+//       publicadas, assim uma função lenta atrasa as próximas mensagens daquele publicador.
+type TopicHandler func(topic, from string, payload []byte)
+
+// pubSubStream
+//
+// English:
+//
+//  Stream opened by this node to a publisher
+//
+// Português:
+//
+//  Stream aberto por este node para um publicador
+type pubSubStream struct {
+	mutex  sync.Mutex
+	client grpcProto.SyncInstances_GrpcFuncPubSubClient
+	cancel context.CancelFunc
+}
+
+// send
+//
+// English:
+//
+//  Sends a request to the publisher. gRPC doesn't allow concurrent sends on the same stream
+//
+// Português:
+//
+//  Envia uma requisição para o publicador. O gRPC não permite envios concorrentes no mesmo stream
+func (e *pubSubStream) send(request *grpcProto.PubSubRequest) (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.client == nil {
+		return
+	}
+
+	return e.client.Send(request)
+}
+
+// Subscribe
+//
+// English:
+//
+//  Subscribes this node to a topic published by the members of the cluster
+//
+//   Input:
+//     topic: name of the topic
+//     handler: function that receives the messages of the topic, replaces the previous handler
+//
+//   Output:
+//     err: ErrInvalidTopic
+//
+//   Note:
+//     * This node opens a gRPC stream to each member of the cluster, including itself, and
+//       registers its topics on it. The members that join later are connected by the synchronism
+//       loop;
+//     * The delivery is at least once: a message is kept by the publisher until this node
+//       acknowledges it and is sent again when the stream is reopened;
+//     * The messages of each publisher are delivered in the order they were published and the
+//       copies sent again are discarded.
+//
+// Português:
+//
+//  Inscreve este node em um tópico publicado pelos membros do cluster
+//
+//   Entrada:
+//     topic: nome do tópico
+//     handler: função que recebe as mensagens do tópico, substitui a função anterior
+//
+//   Saída:
+//     err: ErrInvalidTopic
+//
+//   Nota:
+//     * Este node abre um stream gRPC para cada membro do cluster, incluindo ele mesmo, e registra
+//       os seus tópicos nele. Os membros que entram depois são conectados pelo ciclo de
+//       sincronismo;
+//     * A entrega é pelo menos uma vez: uma mensagem é mantida pelo publicador até este node
+//       confirmá-la e é enviada novamente quando o stream é reaberto;
+//     * As mensagens de cada publicador são entregues na ordem em que foram publicadas e as cópias
+//       enviadas novamente são descartadas.
+func (e *Server) Subscribe(topic string, handler TopicHandler) (err error) {
+	if topic == "" || handler == nil {
+		err = ErrInvalidTopic
+		return
+	}
+
+	var state = e.getPubSub()
+	state.mutex.Lock()
+	state.handlers[topic] = handler
+	state.mutex.Unlock()
+
+	e.sendPubSubTopics()
+	if e.memberList != nil {
+		e.updatePubSub()
+	}
+
+	return
+}
+
+// Unsubscribe
+//
+// English:
+//
+//  Removes the subscription of this node to a topic
+//
+//   Input:
+//     topic: name of the topic
+//
+// Português:
+//
+//  Remove a inscrição deste node em um tópico
+//
+//   Entrada:
+//     topic: nome do tópico
+func (e *Server) Unsubscribe(topic string) {
+	var state = e.getPubSub()
+	state.mutex.Lock()
+	delete(state.handlers, topic)
+	state.mutex.Unlock()
+
+	e.sendPubSubTopics()
+}
+
+// Publish
+//
+// English:
+//
+//  Sends a message to all members subscribed to the topic
+//
+//   Input:
+//     topic: name of the topic
+//     payload: content of the message
+//
+//   Output:
+//     subscribers: number of members subscribed to the topic that will receive the message
+//     err: ErrInvalidTopic
+//
+//   Note:
+//     * Publish() doesn't wait for the delivery, the message is kept for each subscriber until it
+//       is acknowledged;
+//     * Up to kPubSubPendingLimit messages are kept per subscriber, the oldest are discarded when a
+//       subscriber stays disconnected for too long.
+//
+// Português:
+//
+//  Envia uma mensagem para todos os membros inscritos no tópico
+//
+//   Entrada:
+//     topic: nome do tópico
+//     payload: conteúdo da mensagem
+//
+//   Saída:
+//     subscribers: quantidade de membros inscritos no tópico que vão receber a mensagem
+//     err: ErrInvalidTopic
+//
+//   Nota:
+//     * Publish() não espera pela entrega, a mensagem é mantida para cada inscrito até ser
+//       confirmada;
+//     * Até kPubSubPendingLimit mensagens são mantidas por inscrito, as mais antigas são
+//       descartadas quando um inscrito fica desconectado por muito tempo.
+func (e *Server) Publish(topic string, payload []byte) (subscribers int, err error) {
+	if topic == "" {
+		err = ErrInvalidTopic
+		return
+	}
+
+	var discarded []string
+	subscribers, discarded = e.getPubSub().publish(e.localNodeName(), topic, payload)
+	for _, name := range discarded {
+		e.getLogger().Warn("pending message discarded, the subscriber is too slow or disconnected", "subscriber", name, "topic", topic)
+	}
+
+	return
+}
+
+// getPubSub
+//
+// English:
+//
+//  Returns the publish/subscribe state, creating it on first use
+//
+// Português:
+//
+//  Retorna o estado da publicação/inscrição, criando-o no primeiro uso
+func (e *Server) getPubSub() (state *pubSub) {
+	e.pubSubOnce.Do(func() {
+		e.pubSub = newPubSub()
+	})
+
+	return e.pubSub
+}
+
+// updatePubSub
+//
+// English:
+//
+//  Opens the streams to the members not connected yet, when this node has topics, and forgets the
+//  subscribers and publishers that left the cluster. Called by the synchronism loop
+//
+// Português:
+//
+//  Abre os streams para os membros ainda não conectados, quando este node tem tópicos, e esquece os
+//  inscritos e publicadores que saíram do cluster. Chamado pelo ciclo de sincronismo
+func (e *Server) updatePubSub() {
+	var state = e.getPubSub()
+	var subscribed = len(state.topics()) > 0
+
+	var members = make(map[string]bool)
+	for _, member := range e.Members() {
+		members[member.Name] = true
+		if subscribed == true && member.State == MemberAlive && member.GrpcPort != 0 {
+			e.connectPubSub(member.Name)
+		}
+	}
+
+	state.prune(members)
+}
+
+// connectPubSub
+//
+// English:
+//
+//  Opens a stream to the publisher, if there is none
+//
+// Português:
+//
+//  Abre um stream para o publicador, se não existe nenhum
+func (e *Server) connectPubSub(nodeName string) {
+	var state = e.getPubSub()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.stopped == true || state.streams[nodeName] != nil {
+		return
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var stream = &pubSubStream{cancel: cancel}
+	state.streams[nodeName] = stream
+
+	go e.receivePubSub(ctx, nodeName, stream)
+}
+
+// receivePubSub
+//
+// English:
+//
+//  Subscribes the topics of this node on the publisher and delivers the messages received until the
+//  stream ends. The next synchronism loop opens a new stream
+//
+// Português:
+//
+//  Inscreve os tópicos deste node no publicador e entrega as mensagens recebidas até o stream
+//  terminar. O próximo ciclo de sincronismo abre um novo stream
+func (e *Server) receivePubSub(ctx context.Context, nodeName string, stream *pubSubStream) {
+	var state = e.getPubSub()
+	defer func() {
+		stream.cancel()
+
+		state.mutex.Lock()
+		defer state.mutex.Unlock()
+
+		if state.streams[nodeName] == stream {
+			delete(state.streams, nodeName)
+		}
+	}()
+
+	var client, err = e.GetGrpcClient(nodeName)
+	if err != nil {
+		e.getLogger().Debug("pubsub connection failed", "node", nodeName, "error", err)
+		return
+	}
+
+	var grpcStream grpcProto.SyncInstances_GrpcFuncPubSubClient
+	grpcStream, err = client.GrpcFuncPubSub(ctx)
+	if err != nil {
+		e.getLogger().Debug("pubsub connection failed", "node", nodeName, "error", err)
+		return
+	}
+
+	// English: the topics are read under the lock of the stream, so a concurrent Subscribe() can't
+	// send an older list after this one
+	//
+	// Português: os tópicos são lidos sob a trava do stream, assim um Subscribe() concorrente não pode
+	// enviar uma lista mais antiga depois desta
+	stream.mutex.Lock()
+	stream.client = grpcStream
+	err = grpcStream.Send(&grpcProto.PubSubRequest{Node: e.localNodeName(), Subscribe: true, Topics: state.topics()})
+	stream.mutex.Unlock()
+	if err != nil {
+		e.getLogger().Debug("pubsub subscription failed", "node", nodeName, "error", err)
+		return
+	}
+
+	for {
+		var message *grpcProto.PubSubMessage
+		message, err = grpcStream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				e.getLogger().Debug("pubsub stream ended", "node", nodeName, "error", err)
+			}
+			return
+		}
+
+		if handler := state.deliver(message); handler != nil {
+			handler(message.GetTopic(), message.GetFrom(), message.GetPayload())
+		}
+
+		err = stream.send(&grpcProto.PubSubRequest{Ack: message.GetSequence()})
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sendPubSubTopics
+//
+// English:
+//
+//  Sends the current topics of this node to all publishers connected
+//
+// Português:
+//
+//  Envia os tópicos atuais deste node para todos os publicadores conectados
+func (e *Server) sendPubSubTopics() {
+	var state = e.getPubSub()
+
+	state.mutex.Lock()
+	var streams = make([]*pubSubStream, 0, len(state.streams))
+	for _, stream := range state.streams {
+		streams = append(streams, stream)
+	}
+	state.mutex.Unlock()
+
+	for _, stream := range streams {
+		stream.mutex.Lock()
+		if stream.client != nil {
+			_ = stream.client.Send(&grpcProto.PubSubRequest{Node: e.localNodeName(), Subscribe: true, Topics: state.topics()})
+		}
+		stream.mutex.Unlock()
+	}
+}
@@ -0,0 +1,253 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"github.com/helmutkemper/iotmaker.docker.builder.demo/mainProject/grpcProto"
+	"time"
+)
+
+const (
+	//kQueryTimeout
+	//
+	// English:
+	//
+	// Maximum time to wait for the answers of Query() when QueryParams.Timeout is zero.
+	//
+	// Português:
+	//
+	// Tempo máximo de espera pelas respostas de Query() quando QueryParams.Timeout é zero.
+	kQueryTimeout = time.Second * 5
+)
+
+// QueryHandler
+//
+// English:
+//
+//  Function that answers the queries sent by Query() from the other instances
+//
+//   Input:
+//     ctx: context of the query, done when the query finishes on the node that sent it
+//     from: name of the node that sent the query
+//     payload: content of the query
+//
+//   Output:
+//     replay: content of the answer
+//     err: standard error object, received by the node that sent the query as *CallError
+//
+// Português:
+//
+//  Função que responde as consultas enviadas por Query() das demais instâncias
+//
+//   Entrada:
+//     ctx: contexto da consulta, terminado quando a consulta termina no node que a enviou
+//     from: nome do node que enviou a consulta
+//     payload: conteúdo da consulta
+//
+//   Saída:
+//     replay: conteúdo da resposta
+//     err: objeto de erro padrão, recebido pelo node que enviou a consulta como *CallError
+type QueryHandler func(ctx context.Context, from string, payload []byte) (replay []byte, err error)
+
+// HandleQuery
+//
+// English:
+//
+//  Registers the function that answers the queries with the name sent by Query() on the members
+//
+//   Input:
+//     name: name of the query
+//     handler: function that answers the queries. nil removes the query
+//
+//   Output:
+//     err: ErrInvalidMethod for an empty name
+//
+//   Note:
+//     * The queries have their own names, a query and a method of Handle() can have the same name.
+//
+// Português:
+//
+//  Registra a função que responde as consultas com o nome enviadas por Query() nos membros
+//
+//   Entrada:
+//     name: nome da consulta
+//     handler: função que responde as consultas. nil remove a consulta
+//
+//   Saída:
+//     err: ErrInvalidMethod para um nome vazio
+//
+//   Nota:
+//     * As consultas têm os seus próprios nomes, uma consulta e um método de Handle() podem ter o
+//       mesmo nome.
+func (e *Server) HandleQuery(name string, handler QueryHandler) (err error) {
+	if name == "" {
+		err = ErrInvalidMethod
+		return
+	}
+
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	if handler == nil {
+		delete(e.queryHandlers, name)
+		return
+	}
+
+	if e.queryHandlers == nil {
+		e.queryHandlers = make(map[string]QueryHandler)
+	}
+	e.queryHandlers[name] = handler
+	return
+}
+
+// getQueryHandler
+//
+// English:
+//
+//  Returns the function registered by HandleQuery() for the query
+//
+// Português:
+//
+//  Retorna a função registrada por HandleQuery() para a consulta
+func (e *Server) getQueryHandler(name string) (handler QueryHandler) {
+	e.grpcMutex.Lock()
+	defer e.grpcMutex.Unlock()
+
+	return e.queryHandlers[name]
+}
+
+// Query
+//
+// English:
+//
+//  Sends a query to all members, or to the members selected by tags, and collects the answers
+//
+//   Input:
+//     ctx: context of the query, its cancellation finishes the query
+//     name: name of the query, answered by the function registered by HandleQuery() with this name
+//     payload: content of the query
+//     params: targets and timeout of the query
+//
+//   Output:
+//     query: query in progress, see Query.Acks() and Query.Responses()
+//     err: ErrServerNotInitialized, ErrInvalidMethod or ErrInvalidTagSelector
+//
+//   Note:
+//     * The query is sent to all targets at the same time by the gRPC service SyncInstances;
+//     * The nodes without a handler for the query answer with ErrMethodNotFound and the nodes that
+//       can't be reached answer with ErrNodeNotAlive, so they don't hold the query until the
+//       timeout.
+//
+// Português:
+//
+//  Envia uma consulta para todos os membros, ou para os membros selecionados por tags, e coleta as
+//  respostas
+//
+//   Entrada:
+//     ctx: contexto da consulta, o seu cancelamento termina a consulta
+//     name: nome da consulta, respondida pela função registrada por HandleQuery() com este nome
+//     payload: conteúdo da consulta
+//     params: alvos e tempo limite da consulta
+//
+//   Saída:
+//     query: consulta em andamento, veja Query.Acks() e Query.Responses()
+//     err: ErrServerNotInitialized, ErrInvalidMethod ou ErrInvalidTagSelector
+//
+//   Nota:
+//     * A consulta é enviada para todos os alvos ao mesmo tempo pelo serviço gRPC SyncInstances;
+//     * Os nodes sem uma função para a consulta respondem com ErrMethodNotFound e os nodes que não
+//       podem ser alcançados respondem com ErrNodeNotAlive, assim eles não seguram a consulta até o
+//       tempo limite.
+func (e *Server) Query(ctx context.Context, name string, payload []byte, params QueryParams) (query *Query, err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	if name == "" {
+		err = ErrInvalidMethod
+		return
+	}
+
+	var selector TagSelector
+	selector, err = ParseTagSelector(params.Selector)
+	if err != nil {
+		return
+	}
+
+	var targets = make([]string, 0)
+	for _, member := range e.selectMembers(selector, params.OnlyReady) {
+		targets = append(targets, member.Name)
+	}
+
+	var timeout = params.Timeout
+	if timeout <= 0 {
+		timeout = kQueryTimeout
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, timeout)
+	var deadline, _ = ctx.Deadline()
+
+	query = newQuery(name, targets, deadline, cancel)
+	for _, target := range targets {
+		go e.queryNode(ctx, query, target, payload)
+	}
+
+	go func() {
+		<-ctx.Done()
+		query.Close()
+	}()
+
+	return
+}
+
+// queryNode
+//
+// English:
+//
+//  Sends the query to a node and records its acknowledgement and its answer. The errors caused by
+//  the end of the query, by timeout or by Query.Close(), are not answers of the node
+//
+// Português:
+//
+//  Envia a consulta para um node e registra a sua confirmação e a sua resposta. Os erros causados
+//  pelo fim da consulta, por tempo limite ou por Query.Close(), não são respostas do node
+func (e *Server) queryNode(ctx context.Context, query *Query, nodeName string, payload []byte) {
+	var client, err = e.GetGrpcClient(nodeName)
+	if err != nil {
+		query.respond(QueryResponse{From: nodeName, Err: err})
+		return
+	}
+
+	var stream grpcProto.SyncInstances_GrpcFuncQueryClient
+	stream, err = client.GrpcFuncQuery(ctx, &grpcProto.QueryRequest{
+		From:    e.localNodeName(),
+		Name:    query.Name(),
+		Payload: payload,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			query.respond(QueryResponse{From: nodeName, Err: callError(nodeName, query.Name(), err)})
+		}
+		return
+	}
+
+	for {
+		var replay *grpcProto.QueryReplay
+		replay, err = stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				query.respond(QueryResponse{From: nodeName, Err: callError(nodeName, query.Name(), err)})
+			}
+			return
+		}
+
+		if replay.GetAck() == true {
+			query.ack(nodeName)
+			continue
+		}
+
+		query.respond(QueryResponse{From: nodeName, Payload: replay.GetPayload()})
+		return
+	}
+}
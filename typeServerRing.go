@@ -0,0 +1,366 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	//kRingVirtualNodes
+	//
+	// English:
+	//
+	// Default number of points of a node with weight one in the consistent hash ring.
+	//
+	// Português:
+	//
+	// Número padrão de pontos de um node com peso um no anel de hash consistente.
+	kRingVirtualNodes = 128
+
+	//kRingMaxWeight
+	//
+	// English:
+	//
+	// Largest weight of a node in the consistent hash ring. Greater weights, local or received from
+	// the metadata of another member, count as kRingMaxWeight, so a member can't make the others
+	// allocate an unbounded number of points.
+	//
+	// Português:
+	//
+	// Maior peso de um node no anel de hash consistente. Pesos maiores, locais ou recebidos dos
+	// metadados de outro membro, contam como kRingMaxWeight, assim um membro não faz os demais
+	// alocarem um número ilimitado de pontos.
+	kRingMaxWeight = 100
+
+	//kRingChangeBufferSize
+	//
+	// English:
+	//
+	// Default size of the channel returned by SubscribeRingChanges() when bufferSize is less than one.
+	//
+	// Português:
+	//
+	// Tamanho padrão do canal retornado por SubscribeRingChanges() quando bufferSize é menor que um.
+	kRingChangeBufferSize = 16
+)
+
+// RingChange
+//
+// English:
+//
+//  Change of the consistent hash ring caused by a change of the members
+//
+//   Fields:
+//     Nodes: nodes of the new ring
+//     Moved: ranges whose owner changed
+//     Time: time of the change
+//
+// Português:
+//
+//  Mudança do anel de hash consistente causada por uma mudança dos membros
+//
+//   Campos:
+//     Nodes: nodes do novo anel
+//     Moved: faixas cujo dono mudou
+//     Time: momento da mudança
+type RingChange struct {
+	Nodes []string
+	Moved []RingRange
+	Time  time.Time
+}
+
+// SetRingVirtualNodes
+//
+// English:
+//
+//  Defines the number of points of a node with weight one in the consistent hash ring. More points
+//  spread the keys more evenly, at the cost of memory
+//
+//   Input:
+//     virtualNodes: number of points, by default, kRingVirtualNodes
+//
+//   Note:
+//     * Must be called before Init() and must be the same on all members.
+//
+// Português:
+//
+//  Define o número de pontos de um node com peso um no anel de hash consistente. Mais pontos
+//  distribuem as chaves de forma mais uniforme, ao custo de memória
+//
+//   Entrada:
+//     virtualNodes: número de pontos, por padrão, kRingVirtualNodes
+//
+//   Nota:
+//     * Deve ser chamado antes de Init() e deve ser o mesmo em todos os membros.
+func (e *Server) SetRingVirtualNodes(virtualNodes int) {
+	e.ringVirtualNodes = virtualNodes
+}
+
+// SetRingWeight
+//
+// English:
+//
+//  Defines the weight of this node in the consistent hash ring. A node with weight two receives about
+//  twice the keys of a node with weight one
+//
+//   Input:
+//     weight: weight of the node, by default, one, from one to kRingMaxWeight
+//
+//   Note:
+//     * Can be called at any time, the new weight is sent to the other members by the metadata.
+//
+// Português:
+//
+//  Define o peso deste node no anel de hash consistente. Um node com peso dois recebe cerca do dobro
+//  das chaves de um node com peso um
+//
+//   Entrada:
+//     weight: peso do node, por padrão, um, de um até kRingMaxWeight
+//
+//   Nota:
+//     * Pode ser chamado a qualquer momento, o novo peso é enviado aos demais membros pelos metadados.
+func (e *Server) SetRingWeight(weight int) {
+	weight = clampRingWeight(weight)
+	e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.RingWeight = weight
+	})
+
+	if e.memberList == nil {
+		return
+	}
+
+	var err = e.memberList.UpdateNode(e.getDnsCheckInterval())
+	if err != nil {
+		e.getLogger().Warn("node metadata update failed", "error", err)
+	}
+}
+
+// Owner
+//
+// English:
+//
+//  Returns the node that owns the key in the consistent hash ring
+//
+//   Input:
+//     key: key used to shard the work
+//
+//   Output:
+//     node: name of the node. Empty if there is no member
+//
+// Português:
+//
+//  Retorna o node dono da chave no anel de hash consistente
+//
+//   Entrada:
+//     key: chave usada para dividir o trabalho
+//
+//   Saída:
+//     node: nome do node. Vazio se não há membros
+func (e *Server) Owner(key string) (node string) {
+	return e.getRing().ownerOfHash(KeyHash(key))
+}
+
+// Owners
+//
+// English:
+//
+//  Returns up to n distinct nodes for the key, in order of preference, useful for replicas
+//
+//   Input:
+//     key: key used to shard the work
+//     n: maximum number of nodes, limited to the number of members of the ring
+//
+//   Output:
+//     nodes: names of the nodes, the first one is the same returned by Owner(). nil when n is less
+//            than one or the ring is empty
+//
+// Português:
+//
+//  Retorna até n nodes distintos para a chave, em ordem de preferência, útil para réplicas
+//
+//   Entrada:
+//     key: chave usada para dividir o trabalho
+//     n: número máximo de nodes, limitado ao número de membros do anel
+//
+//   Saída:
+//     nodes: nomes dos nodes, o primeiro é o mesmo retornado por Owner(). nil quando n é menor que um
+//            ou o anel está vazio
+func (e *Server) Owners(key string, n int) (nodes []string) {
+	return e.getRing().owners(key, n)
+}
+
+// SubscribeRingChanges
+//
+// English:
+//
+//  Returns a channel that receives the ranges of the ring that moved on each change of the members
+//
+//   Input:
+//     bufferSize: size of the channel buffer. Values less than one use kRingChangeBufferSize
+//
+//   Output:
+//     changes: channel of ring changes
+//     unsubscribe: removes the subscription and closes the channel
+//
+//   Note:
+//     * When the channel is full, the change is discarded and a warning is logged.
+//
+// Português:
+//
+//  Retorna um canal que recebe as faixas do anel que mudaram a cada mudança dos membros
+//
+//   Entrada:
+//     bufferSize: tamanho do buffer do canal. Valores menores que um usam kRingChangeBufferSize
+//
+//   Saída:
+//     changes: canal de mudanças do anel
+//     unsubscribe: remove a inscrição e fecha o canal
+//
+//   Nota:
+//     * Quando o canal está cheio, a mudança é descartada e um aviso é registrado no log.
+func (e *Server) SubscribeRingChanges(bufferSize int) (changes <-chan RingChange, unsubscribe func()) {
+	if bufferSize < 1 {
+		bufferSize = kRingChangeBufferSize
+	}
+
+	var channel = make(chan RingChange, bufferSize)
+
+	e.ringSubscriberMutex.Lock()
+	defer e.ringSubscriberMutex.Unlock()
+
+	if e.ringSubscribers == nil {
+		e.ringSubscribers = make(map[chan RingChange]struct{})
+	}
+	e.ringSubscribers[channel] = struct{}{}
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			e.ringSubscriberMutex.Lock()
+			defer e.ringSubscriberMutex.Unlock()
+
+			delete(e.ringSubscribers, channel)
+			close(channel)
+		})
+	}
+
+	changes = channel
+	return
+}
+
+// getRing
+//
+// English:
+//
+//  Returns the current ring
+//
+// Português:
+//
+//  Retorna o anel atual
+func (e *Server) getRing() (ring *hashRing) {
+	e.ringMutex.RLock()
+	defer e.ringMutex.RUnlock()
+
+	if e.ring == nil {
+		return &hashRing{}
+	}
+
+	return e.ring
+}
+
+// updateRingNode
+//
+// English:
+//
+//  Adds, updates or removes a node of the ring and publishes the moved ranges. Called by the event
+//  delegate, so it must not call memberlist
+//
+//   Input:
+//     name: name of the node
+//     weight: weight of the node, limited to the range from one to kRingMaxWeight
+//     present: false to remove the node
+//
+// Português:
+//
+//  Adiciona, atualiza ou remove um node do anel e publica as faixas que mudaram. Chamado pelo delegate
+//  de eventos, por isto não pode chamar a memberlist
+//
+//   Entrada:
+//     name: nome do node
+//     weight: peso do node, limitado à faixa de um até kRingMaxWeight
+//     present: false para remover o node
+func (e *Server) updateRingNode(name string, weight int, present bool) {
+	weight = clampRingWeight(weight)
+
+	e.ringMutex.Lock()
+	if e.ringNodes == nil {
+		e.ringNodes = make(map[string]int)
+	}
+
+	var currentWeight, found = e.ringNodes[name]
+	if present == found && (present == false || currentWeight == weight) {
+		e.ringMutex.Unlock()
+		return
+	}
+
+	if present == true {
+		e.ringNodes[name] = weight
+	} else {
+		delete(e.ringNodes, name)
+	}
+
+	var virtualNodes = e.ringVirtualNodes
+	if virtualNodes <= 0 {
+		virtualNodes = kRingVirtualNodes
+	}
+
+	var previous = e.ring
+	if previous == nil {
+		previous = &hashRing{}
+	}
+	e.ring = newHashRing(e.ringNodes, virtualNodes)
+
+	var change = RingChange{
+		Nodes: make([]string, 0, len(e.ringNodes)),
+		Moved: movedRanges(previous, e.ring),
+		Time:  time.Now(),
+	}
+	for node := range e.ringNodes {
+		change.Nodes = append(change.Nodes, node)
+	}
+	sort.Strings(change.Nodes)
+	e.ringMutex.Unlock()
+
+	e.ringSubscriberMutex.Lock()
+	defer e.ringSubscriberMutex.Unlock()
+
+	for channel := range e.ringSubscribers {
+		select {
+		case channel <- change:
+		default:
+			e.getLogger().Warn("ring change discarded, subscriber channel is full")
+		}
+	}
+}
+
+// clampRingWeight
+//
+// English:
+//
+//  Returns the weight limited to the range from one to kRingMaxWeight
+//
+// Português:
+//
+//  Retorna o peso limitado à faixa de um até kRingMaxWeight
+func clampRingWeight(weight int) (clamped int) {
+	if weight < 1 {
+		return 1
+	}
+
+	if weight > kRingMaxWeight {
+		return kRingMaxWeight
+	}
+
+	return weight
+}
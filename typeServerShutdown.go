@@ -0,0 +1,199 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	//kLeaveTimeout
+	//
+	// English:
+	//
+	// Maximum time to wait for the leave message to be broadcast to the other members when the
+	// context passed to Shutdown() has no deadline.
+	//
+	// Português:
+	//
+	// Tempo máximo de espera para a mensagem de saída ser transmitida aos demais membros quando o
+	// contexto passado para Shutdown() não tem prazo.
+	kLeaveTimeout = time.Second * 5
+
+	//kLeaveMinTimeout
+	//
+	// English:
+	//
+	// Minimum time to wait for the leave message. memberlist.Leave() waits without limit for a timeout
+	// of zero or less, so a context that expired during Shutdown() still ends the wait.
+	//
+	// Português:
+	//
+	// Tempo mínimo de espera pela mensagem de saída. memberlist.Leave() espera sem limite por um tempo
+	// limite de zero ou menos, assim um contexto que expirou durante Shutdown() ainda termina a espera.
+	kLeaveMinTimeout = time.Millisecond * 10
+)
+
+// ErrServerNotInitialized
+//
+// English:
+//
+//  Returned when a method that depends on Init() is called before it.
+//
+// Português:
+//
+//  Retornado quando um método que depende de Init() é chamado antes dele.
+var ErrServerNotInitialized = errors.New("server not initialized")
+
+// Shutdown
+//
+// English:
+//
+//  Stops the synchronism loop, leaves the cluster, stops the gRPC server and releases the ports.
+//
+//   Input:
+//     ctx: limits the time spent waiting for the sync loop and for the leave message. When ctx has
+//          no deadline, kLeaveTimeout is used for the leave message.
+//
+//   Output:
+//     acknowledged: true if at least one other member was alive and the leave message was
+//                   broadcast before the timeout
+//     err: standard error object
+//
+//   Note:
+//     * Calling Shutdown() more than once is safe, only the first call has effect;
+//     * The memberlist is always shut down, even if the leave message times out.
+//
+// Português:
+//
+//  Para o ciclo de sincronismo, sai do cluster, para o servidor gRPC e libera as portas.
+//
+//   Entrada:
+//     ctx: limita o tempo de espera pelo ciclo de sincronismo e pela mensagem de saída. Quando ctx
+//          não tem prazo, kLeaveTimeout é usado para a mensagem de saída.
+//
+//   Saída:
+//     acknowledged: true se pelo menos um outro membro estava ativo e a mensagem de saída foi
+//                   transmitida antes do tempo limite
+//     err: objeto de erro padrão
+//
+//   Nota:
+//     * Chamar Shutdown() mais de uma vez é seguro, apenas a primeira chamada tem efeito;
+//     * A memberlist é sempre desligada, mesmo que a mensagem de saída exceda o tempo limite.
+func (e *Server) Shutdown(ctx context.Context) (acknowledged bool, err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	var first = false
+	e.shutdownOnce.Do(func() {
+		first = true
+	})
+	if first == false {
+		return
+	}
+
+	e.syncBetweenInstancesTicker.Stop()
+	close(e.syncBetweenInstancesStop)
+
+	select {
+	case <-e.syncBetweenInstancesDone:
+	case <-ctx.Done():
+		err = ctx.Err()
+		e.grpcStop(ctx)
+		e.metricsStop(ctx)
+		_ = e.memberList.Shutdown()
+		e.saveNodeHistory()
+		return
+	}
+
+	var timeout = kLeaveTimeout
+	if deadline, ok := ctx.Deadline(); ok == true {
+		timeout = time.Until(deadline)
+	}
+
+	var othersAlive = e.memberList.NumMembers() > 1
+
+	// English: announces the leave by the metadata, so the other members don't report a failure
+	// Português: anuncia a saída pelos metadados, assim os demais membros não reportam uma falha
+	var start = time.Now()
+	e.updateNodeMeta(func(meta *nodeMeta) {
+		meta.Leaving = true
+	})
+	if err = e.memberList.UpdateNode(timeout); err != nil {
+		e.getLogger().Warn("node metadata update failed", "error", err)
+	}
+
+	timeout -= time.Since(start)
+	if timeout < kLeaveMinTimeout {
+		timeout = kLeaveMinTimeout
+	}
+	err = e.memberList.Leave(timeout)
+	acknowledged = err == nil && othersAlive
+
+	e.grpcStop(ctx)
+	e.metricsStop(ctx)
+
+	var errShutdown = e.memberList.Shutdown()
+	e.saveNodeHistory()
+	if err == nil {
+		err = errShutdown
+	}
+
+	return
+}
+
+// Kill
+//
+// English:
+//
+//  Stops the instance without leaving the cluster, as in a crash. The other members detect the
+//  failure by the failure detector and receive the NodeFailed event.
+//
+//   Output:
+//     err: standard error object
+//
+//   Note:
+//     * Meant for failure tests, use Shutdown() to stop the instance;
+//     * The node history is not saved;
+//     * Calling Kill() after Shutdown(), or more than once, has no effect.
+//
+// Português:
+//
+//  Para a instância sem sair do cluster, como em uma falha. Os demais membros detectam a falha pelo
+//  detector de falhas e recebem o evento NodeFailed.
+//
+//   Saída:
+//     err: objeto de erro padrão
+//
+//   Nota:
+//     * Feito para testes de falha, use Shutdown() para parar a instância;
+//     * O histórico de nodes não é salvo;
+//     * Chamar Kill() depois de Shutdown(), ou mais de uma vez, não tem efeito.
+func (e *Server) Kill() (err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	var first = false
+	e.shutdownOnce.Do(func() {
+		first = true
+	})
+	if first == false {
+		return
+	}
+
+	e.syncBetweenInstancesTicker.Stop()
+	close(e.syncBetweenInstancesStop)
+	<-e.syncBetweenInstancesDone
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	e.grpcStop(ctx)
+	e.metricsStop(ctx)
+	err = e.memberList.Shutdown()
+	return
+}
@@ -0,0 +1,176 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"github.com/hashicorp/memberlist"
+	"sort"
+)
+
+// IsReady
+//
+// English:
+//
+//  Returns true if this instance passed all readiness and liveness checks in the last cycle
+//
+// Português:
+//
+//  Retorna true se esta instância passou em todas as verificações de prontidão e de vida no último
+//  ciclo
+func (e *Server) IsReady() (ready bool) {
+	ready, _, _ = e.getHealth()
+	return
+}
+
+// IsLive
+//
+// English:
+//
+//  Returns true if this instance passed all liveness checks in the last cycle
+//
+// Português:
+//
+//  Retorna true se esta instância passou em todas as verificações de vida no último ciclo
+func (e *Server) IsLive() (live bool) {
+	_, live, _ = e.getHealth()
+	return
+}
+
+// LocalAddress
+//
+// English:
+//
+//  Returns the IP address of this node, as announced to the other members
+//
+// Português:
+//
+//  Retorna o endereço IP deste node, como anunciado aos demais membros
+func (e *Server) LocalAddress() (address string) {
+	e.stateMutex.RLock()
+	defer e.stateMutex.RUnlock()
+
+	return e.thisNodeAddress
+}
+
+// LocalName
+//
+// English:
+//
+//  Returns the name of this node in the cluster
+//
+// Português:
+//
+//  Retorna o nome deste node no cluster
+func (e *Server) LocalName() (name string) {
+	return e.localNodeName()
+}
+
+// Members
+//
+// English:
+//
+//  Returns a snapshot of the alive members of the cluster, including this node, ordered by name
+//
+//   Note:
+//     * The snapshot is a copy, it doesn't change with the cluster and can be kept by the caller.
+//
+// Português:
+//
+//  Retorna uma cópia dos membros ativos do cluster, incluindo este node, ordenados pelo nome
+//
+//   Nota:
+//     * A cópia não muda com o cluster e pode ser guardada por quem chamou.
+func (e *Server) Members() (members []Member) {
+	e.membersMutex.RLock()
+	defer e.membersMutex.RUnlock()
+
+	members = make([]Member, 0, len(e.members))
+	for _, member := range e.members {
+		members = append(members, member.copy())
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+	return
+}
+
+// getMember
+//
+// English:
+//
+//  Returns a copy of an alive member
+//
+// Português:
+//
+//  Retorna uma cópia de um membro ativo
+func (e *Server) getMember(name string) (member Member, found bool) {
+	e.membersMutex.RLock()
+	defer e.membersMutex.RUnlock()
+
+	member, found = e.members[name]
+	member = member.copy()
+	return
+}
+
+// storeMember
+//
+// English:
+//
+//  Copies the memberlist node into the members snapshot.
+//
+//  memberlist.Members() and memberlist.LocalNode() return pointers to the internal state of
+//  memberlist, which is changed by the gossip without synchronism, so the fields of the node can
+//  only be read safely inside the event delegate. Called by the event delegate
+//
+// Português:
+//
+//  Copia o node da memberlist para a cópia dos membros.
+//
+//  memberlist.Members() e memberlist.LocalNode() retornam ponteiros para o estado interno da
+//  memberlist, que é alterado pela fofoca sem sincronismo, por isto os campos do node só podem ser
+//  lidos com segurança dentro do delegate de eventos. Chamado pelo delegate de eventos
+func (e *Server) storeMember(node *memberlist.Node) (previous Member, found bool) {
+	var meta = decodeNodeMeta(node.Meta)
+	var member = Member{
+		Name:     node.Name,
+		ID:       meta.ID,
+		Address:  node.Addr.String(),
+		Family:   addressFamilyOfIP(node.Addr),
+		Port:     int(node.Port),
+		State:    MemberAlive,
+		Ready:    meta.Ready,
+		Live:     meta.Live,
+		Meta:     append([]byte{}, node.Meta...),
+		GrpcPort: meta.GrpcPort,
+		Tags:     meta.Tags,
+	}
+
+	if meta.Leaving == true {
+		member.State = MemberLeaving
+	}
+
+	e.membersMutex.Lock()
+	defer e.membersMutex.Unlock()
+
+	if e.members == nil {
+		e.members = make(map[string]Member)
+	}
+	previous, found = e.members[node.Name]
+	e.members[node.Name] = member
+	return
+}
+
+// removeMember
+//
+// English:
+//
+//  Removes a member that left or failed from the members snapshot. Called by the event delegate
+//
+// Português:
+//
+//  Remove da cópia dos membros um membro que saiu ou falhou. Chamado pelo delegate de eventos
+func (e *Server) removeMember(name string) {
+	e.membersMutex.Lock()
+	defer e.membersMutex.Unlock()
+
+	delete(e.members, name)
+}
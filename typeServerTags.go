@@ -0,0 +1,308 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/memberlist"
+)
+
+const (
+	// TagRole
+	//
+	// English: suggested key for the role of the node, for example "worker"
+	//
+	// Português: chave sugerida para a função do node, por exemplo "worker"
+	TagRole = "role"
+
+	// TagVersion
+	//
+	// English: suggested key for the version of the application
+	//
+	// Português: chave sugerida para a versão da aplicação
+	TagVersion = "version"
+
+	// TagZone
+	//
+	// English: suggested key for the zone or data center of the node
+	//
+	// Português: chave sugerida para a zona ou data center do node
+	TagZone = "zone"
+
+	// TagBuild
+	//
+	// English: suggested key for the hash of the build
+	//
+	// Português: chave sugerida para o hash do build
+	TagBuild = "build"
+)
+
+var (
+	// ErrInvalidTagKey
+	//
+	// English: the key of the tag is empty or has a space or one of the characters =!,
+	//
+	// Português: a chave da tag é vazia ou tem um espaço ou um dos caracteres =!,
+	ErrInvalidTagKey = errors.New("invalid tag key")
+
+	// ErrNodeMetaTooLarge
+	//
+	// English: the node metadata, tags included, exceeds the memberlist limit of 512 bytes
+	//
+	// Português: os metadados do node, tags incluídas, excedem o limite da memberlist de 512 bytes
+	ErrNodeMetaTooLarge = errors.New("node metadata exceeds the memberlist limit")
+)
+
+// SetTag
+//
+// English:
+//
+//  Defines a tag of this node, published to the other members by the node metadata
+//
+//   Input:
+//     key: key of the tag, see TagRole, TagVersion, TagZone and TagBuild
+//     value: value of the tag
+//
+//   Output:
+//     err: ErrInvalidTagKey or ErrNodeMetaTooLarge
+//
+//   Note:
+//     * Can be called at any time, the other members receive the NodeUpdated event;
+//     * The gRPC port is always published, see Member.GrpcPort;
+//     * The metadata of the node, tags included, is limited to 512 bytes. Room is reserved for the
+//       fixed fields, such as the ID and the gRPC port, so the tags can use less than 512 bytes.
+//
+// Português:
+//
+//  Define uma tag deste node, publicada para os demais membros pelos metadados do node
+//
+//   Entrada:
+//     key: chave da tag, veja TagRole, TagVersion, TagZone e TagBuild
+//     value: valor da tag
+//
+//   Saída:
+//     err: ErrInvalidTagKey ou ErrNodeMetaTooLarge
+//
+//   Nota:
+//     * Pode ser chamado a qualquer momento, os demais membros recebem o evento NodeUpdated;
+//     * A porta gRPC é sempre publicada, veja Member.GrpcPort;
+//     * Os metadados do node, tags incluídas, são limitados a 512 bytes. Espaço é reservado para os
+//       campos fixos, como o ID e a porta gRPC, por isto as tags podem usar menos de 512 bytes.
+func (e *Server) SetTag(key, value string) (err error) {
+	if validTagKey(key) == false {
+		err = fmt.Errorf("%w: %q", ErrInvalidTagKey, key)
+		return
+	}
+
+	return e.updateTags(func(tags map[string]string) {
+		tags[key] = value
+	})
+}
+
+// DeleteTag
+//
+// English:
+//
+//  Removes a tag of this node
+//
+//   Input:
+//     key: key of the tag
+//
+//   Output:
+//     err: standard error object
+//
+// Português:
+//
+//  Remove uma tag deste node
+//
+//   Entrada:
+//     key: chave da tag
+//
+//   Saída:
+//     err: objeto de erro padrão
+func (e *Server) DeleteTag(key string) (err error) {
+	return e.updateTags(func(tags map[string]string) {
+		delete(tags, key)
+	})
+}
+
+// SetTags
+//
+// English:
+//
+//  Replaces all tags of this node
+//
+//   Input:
+//     tags: new tags, the map is copied
+//
+//   Output:
+//     err: ErrInvalidTagKey or ErrNodeMetaTooLarge, in which case the tags don't change
+//
+// Português:
+//
+//  Troca todas as tags deste node
+//
+//   Entrada:
+//     tags: novas tags, o mapa é copiado
+//
+//   Saída:
+//     err: ErrInvalidTagKey ou ErrNodeMetaTooLarge, caso em que as tags não mudam
+func (e *Server) SetTags(tags map[string]string) (err error) {
+	var copied = make(map[string]string, len(tags))
+	for key, value := range tags {
+		if validTagKey(key) == false {
+			err = fmt.Errorf("%w: %q", ErrInvalidTagKey, key)
+			return
+		}
+
+		copied[key] = value
+	}
+
+	return e.updateTags(func(current map[string]string) {
+		for key := range current {
+			delete(current, key)
+		}
+
+		for key, value := range copied {
+			current[key] = value
+		}
+	})
+}
+
+// updateTags
+//
+// English:
+//
+//  Changes the tags of this node, the read and the write are made under the same lock, so
+//  concurrent changes of different tags are never lost
+//
+//   Input:
+//     update: function that changes a copy of the current tags
+//
+//   Output:
+//     err: ErrNodeMetaTooLarge, in which case the tags don't change
+//
+// Português:
+//
+//  Altera as tags deste node, a leitura e a escrita são feitas sob a mesma trava, assim alterações
+//  concorrentes de tags diferentes nunca são perdidas
+//
+//   Entrada:
+//     update: função que altera uma cópia das tags atuais
+//
+//   Saída:
+//     err: ErrNodeMetaTooLarge, caso em que as tags não mudam
+func (e *Server) updateTags(update func(tags map[string]string)) (err error) {
+	// English: the fixed fields are checked at their largest values, Init() fills in the ID and the gRPC
+	// port and the health, the leave, the priority and the weight change later
+	// Português: os campos fixos são verificados nos seus maiores valores, Init() preenche o ID e a porta
+	// gRPC e a saúde, a saída, a prioridade e o peso mudam depois
+	e.nodeMetaMutex.Lock()
+	var meta = e.nodeMeta
+	meta.Tags = make(map[string]string, len(e.nodeMeta.Tags))
+	for key, value := range e.nodeMeta.Tags {
+		meta.Tags[key] = value
+	}
+	update(meta.Tags)
+
+	if meta.fits(memberlist.MetaMaxSize) == false {
+		e.nodeMetaMutex.Unlock()
+		err = ErrNodeMetaTooLarge
+		return
+	}
+	e.nodeMeta = meta
+	e.nodeMetaMutex.Unlock()
+
+	if e.memberList == nil {
+		return
+	}
+
+	err = e.memberList.UpdateNode(e.getDnsCheckInterval())
+	if err != nil {
+		e.getLogger().Warn("node metadata update failed", "error", err)
+		err = nil
+	}
+
+	return
+}
+
+// Tags
+//
+// English:
+//
+//  Returns a copy of the tags of this node
+//
+// Português:
+//
+//  Retorna uma cópia das tags deste node
+func (e *Server) Tags() (tags map[string]string) {
+	var meta = e.getNodeMeta()
+
+	tags = make(map[string]string, len(meta.Tags))
+	for key, value := range meta.Tags {
+		tags[key] = value
+	}
+
+	return
+}
+
+// SelectMembers
+//
+// English:
+//
+//  Returns the members whose tags satisfy the selector, ordered by name
+//
+//   Input:
+//     selector: selector in the format of ParseTagSelector(), for example "role=worker,zone=a"
+//     onlyReady: true to return only the ready members that are not leaving
+//
+//   Output:
+//     members: selected members
+//     err: ErrInvalidTagSelector
+//
+// Português:
+//
+//  Retorna os membros cujas tags satisfazem o seletor, ordenados pelo nome
+//
+//   Entrada:
+//     selector: seletor no formato de ParseTagSelector(), por exemplo "role=worker,zone=a"
+//     onlyReady: true para retornar apenas os membros prontos que não estão saindo
+//
+//   Saída:
+//     members: membros selecionados
+//     err: ErrInvalidTagSelector
+func (e *Server) SelectMembers(selector string, onlyReady bool) (members []Member, err error) {
+	var tagSelector TagSelector
+	tagSelector, err = ParseTagSelector(selector)
+	if err != nil {
+		return
+	}
+
+	members = e.selectMembers(tagSelector, onlyReady)
+	return
+}
+
+// selectMembers
+//
+// English:
+//
+//  Returns the members whose tags satisfy the parsed selector
+//
+// Português:
+//
+//  Retorna os membros cujas tags satisfazem o seletor já convertido
+func (e *Server) selectMembers(selector TagSelector, onlyReady bool) (members []Member) {
+	members = make([]Member, 0)
+	for _, member := range e.Members() {
+		if onlyReady == true && (member.Ready == false || member.State != MemberAlive) {
+			continue
+		}
+
+		if selector.Matches(member.Tags) == false {
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	return
+}
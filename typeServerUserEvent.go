@@ -0,0 +1,258 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidEventName
+//
+// English:
+//
+//  Returned when the name of the event is empty.
+//
+// Português:
+//
+//  Retornado quando o nome do evento é vazio.
+var ErrInvalidEventName = errors.New("invalid event name")
+
+// ErrUserEventTooLarge
+//
+// English:
+//
+//  Returned when the encoded event doesn't fit kGossipMessageMaxSize.
+//
+// Português:
+//
+//  Retornado quando o evento codificado não cabe em kGossipMessageMaxSize.
+var ErrUserEventTooLarge = errors.New("user event is larger than the gossip limit")
+
+// UserEventHandler
+//
+// English:
+//
+//  Function that receives the events sent by Server.Broadcast()
+//
+//   Input:
+//     event: the most recent event of its name in the coalescing window
+//
+// Português:
+//
+//  Função que recebe os eventos enviados por Server.Broadcast()
+//
+//   Entrada:
+//     event: o evento mais recente do seu nome na janela de união
+type UserEventHandler func(event UserEvent)
+
+// SetUserEventHandler
+//
+// English:
+//
+//  Defines the function that receives the events sent by Broadcast(), including the events sent by
+//  this node
+//
+//   Input:
+//     handler: function called once per event ID, after the coalescing window
+//
+// Português:
+//
+//  Define a função que recebe os eventos enviados por Broadcast(), incluindo os eventos enviados por
+//  este node
+//
+//   Entrada:
+//     handler: função chamada uma vez por ID de evento, depois da janela de união
+func (e *Server) SetUserEventHandler(handler UserEventHandler) {
+	var state = e.getUserEvents()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	e.userEventHandler = handler
+}
+
+// SetUserEventCoalesceWindow
+//
+// English:
+//
+//  Defines the time during which the events with the same name are coalesced into the most recent
+//  one, by default, kUserEventCoalesceWindow
+//
+//   Input:
+//     window: coalescing window
+//
+//   Note:
+//     * Must be called before Init().
+//
+// Português:
+//
+//  Define o tempo durante o qual os eventos com o mesmo nome são unidos no mais recente, por padrão,
+//  kUserEventCoalesceWindow
+//
+//   Entrada:
+//     window: janela de união
+//
+//   Nota:
+//     * Deve ser chamado antes de Init().
+func (e *Server) SetUserEventCoalesceWindow(window time.Duration) {
+	e.userEventWindow = window
+}
+
+// Broadcast
+//
+// English:
+//
+//  Sends a fire-and-forget event to all members of the cluster by gossip
+//
+//   Input:
+//     name: name of the event, for example, "reload config"
+//     payload: content of the event
+//
+//   Output:
+//     err: ErrServerNotInitialized, ErrInvalidEventName, ErrUserEventTooLarge or standard error object
+//
+//   Note:
+//     * Each member delivers the event once, the copies received by gossip are discarded by the ID;
+//     * The events with the same name received inside the coalescing window are delivered as only
+//       one, the one with the greatest Lamport time;
+//     * The event must fit a gossip message, kGossipMessageMaxSize bytes once encoded, which leaves
+//       about 700 bytes for the name and the payload;
+//     * There is no delivery guarantee, the members that are disconnected don't receive the event.
+//
+// Português:
+//
+//  Envia um evento sem confirmação para todos os membros do cluster por fofoca
+//
+//   Entrada:
+//     name: nome do evento, por exemplo, "reload config"
+//     payload: conteúdo do evento
+//
+//   Saída:
+//     err: ErrServerNotInitialized, ErrInvalidEventName, ErrUserEventTooLarge ou objeto de erro
+//          padrão
+//
+//   Nota:
+//     * Cada membro entrega o evento uma vez, as cópias recebidas por fofoca são descartadas pelo ID;
+//     * Os eventos com o mesmo nome recebidos dentro da janela de união são entregues como apenas
+//       um, o de maior tempo de Lamport;
+//     * O evento deve caber em uma mensagem de fofoca, kGossipMessageMaxSize bytes depois de
+//       codificado, o que deixa cerca de 700 bytes para o nome e o conteúdo;
+//     * Não há garantia de entrega, os membros desconectados não recebem o evento.
+func (e *Server) Broadcast(name string, payload []byte) (err error) {
+	if e.memberList == nil {
+		err = ErrServerNotInitialized
+		return
+	}
+
+	if name == "" {
+		err = ErrInvalidEventName
+		return
+	}
+
+	var state = e.getUserEvents()
+	var event = UserEvent{
+		ID:      newMessageID(),
+		Name:    name,
+		Payload: append([]byte{}, payload...),
+		LTime:   state.clock.increment(),
+		From:    e.localNodeName(),
+	}
+
+	var data []byte
+	data, err = encodeGossipMessage(kGossipMessageUserEvent, event)
+	if err != nil {
+		return
+	}
+
+	if len(data) > kGossipMessageMaxSize {
+		err = ErrUserEventTooLarge
+		return
+	}
+
+	state.remember(event)
+	e.queueBroadcast("event:"+event.ID, kGossipMessageUserEvent, event)
+	state.coalesce(event, e.getUserEventWindow(), e.deliverUserEvent)
+	return
+}
+
+// getUserEvents
+//
+// English:
+//
+//  Returns the state of the user events, creating it on first use
+//
+// Português:
+//
+//  Retorna o estado dos eventos de usuário, criando-o no primeiro uso
+func (e *Server) getUserEvents() (state *userEvents) {
+	e.userEventOnce.Do(func() {
+		e.userEvents = newUserEvents()
+	})
+
+	return e.userEvents
+}
+
+// getUserEventWindow
+//
+// English:
+//
+//  Returns the coalescing window, applying the default value
+//
+// Português:
+//
+//  Retorna a janela de união, aplicando o valor padrão
+func (e *Server) getUserEventWindow() (window time.Duration) {
+	if e.userEventWindow <= 0 {
+		return kUserEventCoalesceWindow
+	}
+
+	return e.userEventWindow
+}
+
+// mergeUserEventMessage
+//
+// English:
+//
+//  Receives an event by gossip, relays it when it is new and holds it for the coalescing window
+//
+// Português:
+//
+//  Recebe um evento por fofoca, retransmite-o quando é novo e o segura pela janela de união
+func (e *Server) mergeUserEventMessage(body []byte) {
+	var event UserEvent
+	var err = json.Unmarshal(body, &event)
+	if err != nil {
+		e.getLogger().Warn("invalid user event message", "error", err)
+		return
+	}
+
+	var state = e.getUserEvents()
+	if state.accept(event) == false {
+		return
+	}
+
+	// English: as in mergeKeyValueMessage(), the events that were new to this member are relayed
+	// Português: como em mergeKeyValueMessage(), os eventos que eram novos para este membro são
+	// retransmitidos
+	e.queueBroadcast("event:"+event.ID, kGossipMessageUserEvent, event)
+	state.coalesce(event, e.getUserEventWindow(), e.deliverUserEvent)
+}
+
+// deliverUserEvent
+//
+// English:
+//
+//  Calls the function defined by SetUserEventHandler() at the end of the coalescing window
+//
+// Português:
+//
+//  Chama a função definida por SetUserEventHandler() no fim da janela de união
+func (e *Server) deliverUserEvent(event UserEvent) {
+	var state = e.getUserEvents()
+	state.mutex.Lock()
+	var handler = e.userEventHandler
+	state.mutex.Unlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
@@ -0,0 +1,215 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTagSelector
+//
+// English: the text of the selector doesn't follow the key=value,key!=value,key,!key format
+//
+// Português: o texto do seletor não segue o formato chave=valor,chave!=valor,chave,!chave
+var ErrInvalidTagSelector = errors.New("invalid tag selector")
+
+// tagOperator
+//
+// English:
+//
+//  Comparison made by a requirement of the selector
+//
+// Português:
+//
+//  Comparação feita por um requisito do seletor
+type tagOperator int
+
+const (
+	kTagEquals tagOperator = iota
+	kTagNotEquals
+	kTagExists
+	kTagNotExists
+)
+
+// tagRequirement
+//
+// English:
+//
+//  One requirement of the selector
+//
+// Português:
+//
+//  Um requisito do seletor
+type tagRequirement struct {
+	key      string
+	operator tagOperator
+	value    string
+}
+
+// matches
+//
+// English:
+//
+//  Returns true if the tags satisfy the requirement
+//
+// Português:
+//
+//  Retorna true se as tags satisfazem o requisito
+func (e tagRequirement) matches(tags map[string]string) bool {
+	var value, found = tags[e.key]
+
+	switch e.operator {
+	case kTagEquals:
+		return found == true && value == e.value
+	case kTagNotEquals:
+		return found == false || value != e.value
+	case kTagExists:
+		return found == true
+	case kTagNotExists:
+		return found == false
+	}
+
+	return false
+}
+
+// TagSelector
+//
+// English:
+//
+//  Set of requirements over the tags of a member, all of them must be satisfied
+//
+// Português:
+//
+//  Conjunto de requisitos sobre as tags de um membro, todos eles devem ser satisfeitos
+type TagSelector struct {
+	requirements []tagRequirement
+}
+
+// ParseTagSelector
+//
+// English:
+//
+//  Converts the text of a selector into a TagSelector
+//
+//   Input:
+//     text: requirements separated by commas
+//       key=value: the tag exists and has the value
+//       key!=value: the tag doesn't exist or has another value
+//       key: the tag exists
+//       !key: the tag doesn't exist
+//
+//   Output:
+//     selector: selector ready for use. An empty text selects all members
+//     err: ErrInvalidTagSelector
+//
+//   Example:
+//     "role=worker,zone=a,!draining"
+//
+// Português:
+//
+//  Converte o texto de um seletor em um TagSelector
+//
+//   Entrada:
+//     text: requisitos separados por vírgulas
+//       chave=valor: a tag existe e tem o valor
+//       chave!=valor: a tag não existe ou tem outro valor
+//       chave: a tag existe
+//       !chave: a tag não existe
+//
+//   Saída:
+//     selector: seletor pronto para uso. Um texto vazio seleciona todos os membros
+//     err: ErrInvalidTagSelector
+//
+//   Exemplo:
+//     "role=worker,zone=a,!draining"
+func ParseTagSelector(text string) (selector TagSelector, err error) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	for _, part := range strings.Split(text, ",") {
+		var requirement tagRequirement
+		part = strings.TrimSpace(part)
+
+		switch {
+		case strings.Contains(part, "!="):
+			var pair = strings.SplitN(part, "!=", 2)
+			requirement = tagRequirement{key: strings.TrimSpace(pair[0]), operator: kTagNotEquals, value: strings.TrimSpace(pair[1])}
+		case strings.Contains(part, "="):
+			var pair = strings.SplitN(strings.Replace(part, "==", "=", 1), "=", 2)
+			requirement = tagRequirement{key: strings.TrimSpace(pair[0]), operator: kTagEquals, value: strings.TrimSpace(pair[1])}
+		case strings.HasPrefix(part, "!"):
+			requirement = tagRequirement{key: strings.TrimSpace(part[1:]), operator: kTagNotExists}
+		default:
+			requirement = tagRequirement{key: part, operator: kTagExists}
+		}
+
+		if validTagKey(requirement.key) == false || strings.ContainsAny(requirement.value, "=!,") {
+			err = fmt.Errorf("%w: %q", ErrInvalidTagSelector, part)
+			return
+		}
+
+		selector.requirements = append(selector.requirements, requirement)
+	}
+
+	return
+}
+
+// Matches
+//
+// English:
+//
+//  Returns true if the tags satisfy all requirements of the selector
+//
+// Português:
+//
+//  Retorna true se as tags satisfazem todos os requisitos do seletor
+func (e TagSelector) Matches(tags map[string]string) bool {
+	for _, requirement := range e.requirements {
+		if requirement.matches(tags) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String
+//
+// English:
+//
+//  Returns the selector in the format accepted by ParseTagSelector()
+//
+// Português:
+//
+//  Retorna o seletor no formato aceito por ParseTagSelector()
+func (e TagSelector) String() string {
+	var parts = make([]string, 0, len(e.requirements))
+	for _, requirement := range e.requirements {
+		switch requirement.operator {
+		case kTagEquals:
+			parts = append(parts, requirement.key+"="+requirement.value)
+		case kTagNotEquals:
+			parts = append(parts, requirement.key+"!="+requirement.value)
+		case kTagExists:
+			parts = append(parts, requirement.key)
+		case kTagNotExists:
+			parts = append(parts, "!"+requirement.key)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// validTagKey
+//
+// English:
+//
+//  Returns true if the key is not empty and has no space or character used by the selectors
+//
+// Português:
+//
+//  Retorna true se a chave não é vazia e não tem espaço nem caractere usado pelos seletores
+func validTagKey(key string) bool {
+	return key != "" && strings.ContainsAny(key, " \t=!,") == false
+}
@@ -0,0 +1,110 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestParseTagSelector(t *testing.T) {
+	var tests = []struct {
+		text    string
+		want    string
+		invalid bool
+	}{
+		{text: "", want: ""},
+		{text: "  ", want: ""},
+		{text: "role=worker", want: "role=worker"},
+		{text: "role==worker", want: "role=worker"},
+		{text: " role = worker , zone!=a ", want: "role=worker,zone!=a"},
+		{text: "gpu,!draining", want: "gpu,!draining"},
+		{text: "role=", want: "role="},
+		{text: "=worker", invalid: true},
+		{text: "!", invalid: true},
+		{text: "role=work=er", invalid: true},
+		{text: "bad key=1", invalid: true},
+		{text: "role=worker,", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var selector, err = ParseTagSelector(test.text)
+			if test.invalid == true {
+				if errors.Is(err, ErrInvalidTagSelector) == false {
+					t.Fatalf("ParseTagSelector(%q) error = %v, want ErrInvalidTagSelector", test.text, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTagSelector(%q) error = %v", test.text, err)
+			}
+
+			if selector.String() != test.want {
+				t.Fatalf("ParseTagSelector(%q) = %q, want %q", test.text, selector.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestTagSelectorMatches(t *testing.T) {
+	var tags = map[string]string{TagRole: "worker", TagZone: "a", "gpu": ""}
+
+	var tests = []struct {
+		text string
+		want bool
+	}{
+		{text: "", want: true},
+		{text: "role=worker", want: true},
+		{text: "role=api", want: false},
+		{text: "role!=api", want: true},
+		{text: "role!=worker", want: false},
+		{text: "build!=1", want: true},
+		{text: "gpu", want: true},
+		{text: "gpu=", want: true},
+		{text: "build", want: false},
+		{text: "!build", want: true},
+		{text: "!gpu", want: false},
+		{text: "role=worker,zone=a", want: true},
+		{text: "role=worker,zone=b", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var selector, err = ParseTagSelector(test.text)
+			if err != nil {
+				t.Fatalf("ParseTagSelector(%q) error = %v", test.text, err)
+			}
+
+			if selector.Matches(tags) != test.want {
+				t.Fatalf("%q matches %v = %v, want %v", test.text, tags, !test.want, test.want)
+			}
+		})
+	}
+
+	var selector, _ = ParseTagSelector("!role")
+	if selector.Matches(nil) == false {
+		t.Fatal("!role doesn't match a member without tags")
+	}
+}
+
+func TestSetTagConcurrent(t *testing.T) {
+	var server = &Server{}
+	var keys = []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	var wait sync.WaitGroup
+	for _, key := range keys {
+		wait.Add(1)
+		go func(key string) {
+			defer wait.Done()
+			if err := server.SetTag(key, "value"); err != nil {
+				t.Error(err)
+			}
+		}(key)
+	}
+	wait.Wait()
+
+	if tags := server.Tags(); len(tags) != len(keys) {
+		t.Fatalf("Tags() = %v, want %d tags", tags, len(keys))
+	}
+}
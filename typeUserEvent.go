@@ -0,0 +1,200 @@
+package iotmaker_docker_builder_demo
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	//kUserEventBuffer
+	//
+	// English:
+	//
+	// Number of Lamport times an event is remembered to discard its copies. Older events are
+	// discarded as repeated.
+	//
+	// Português:
+	//
+	// Quantidade de tempos de Lamport que um evento é lembrado para descartar as suas cópias. Eventos
+	// mais antigos são descartados como repetidos.
+	kUserEventBuffer = 1024
+
+	//kUserEventCoalesceWindow
+	//
+	// English:
+	//
+	// Time during which the events with the same name are coalesced into the most recent one.
+	//
+	// Português:
+	//
+	// Tempo durante o qual os eventos com o mesmo nome são unidos no mais recente.
+	kUserEventCoalesceWindow = time.Millisecond * 250
+)
+
+// UserEvent
+//
+// English:
+//
+//  Event sent to the whole cluster by Server.Broadcast()
+//
+//   Fields:
+//     ID: unique ID of the event
+//     Name: name of the event, for example, "flush caches"
+//     Payload: content of the event
+//     LTime: Lamport time of the event, greater than the time of the events seen by the sender
+//     From: name of the node that sent the event
+//     Coalesced: number of older events with the same name replaced by this one
+//
+// Português:
+//
+//  Evento enviado para todo o cluster por Server.Broadcast()
+//
+//   Campos:
+//     ID: ID único do evento
+//     Name: nome do evento, por exemplo, "flush caches"
+//     Payload: conteúdo do evento
+//     LTime: tempo de Lamport do evento, maior que o tempo dos eventos vistos por quem enviou
+//     From: nome do node que enviou o evento
+//     Coalesced: quantidade de eventos mais antigos com o mesmo nome substituídos por este
+type UserEvent struct {
+	ID        string `json:"i"`
+	Name      string `json:"n"`
+	Payload   []byte `json:"p,omitempty"`
+	LTime     uint64 `json:"l"`
+	From      string `json:"f"`
+	Coalesced int    `json:"-"`
+}
+
+// userEvents
+//
+// English:
+//
+//  Lamport clock, IDs seen and events waiting for the end of the coalescing window
+//
+// Português:
+//
+//  Relógio de Lamport, IDs vistos e eventos esperando o fim da janela de união
+type userEvents struct {
+	clock   lamportClock
+	mutex   sync.Mutex
+	seen    map[string]uint64
+	pending map[string]*UserEvent
+}
+
+// newUserEvents
+//
+// English:
+//
+//  Returns the state without events
+//
+// Português:
+//
+//  Retorna o estado sem eventos
+func newUserEvents() (state *userEvents) {
+	return &userEvents{
+		seen:    make(map[string]uint64),
+		pending: make(map[string]*UserEvent),
+	}
+}
+
+// accept
+//
+// English:
+//
+//  Advances the clock and returns true if the event is new, false for a copy or an event older
+//  than kUserEventBuffer
+//
+// Português:
+//
+//  Avança o relógio e retorna true se o evento é novo, false para uma cópia ou um evento mais antigo
+//  que kUserEventBuffer
+func (e *userEvents) accept(event UserEvent) (accepted bool) {
+	var now = e.clock.time()
+	e.clock.witness(event.LTime)
+
+	if event.LTime+kUserEventBuffer < now {
+		return
+	}
+
+	return e.remember(event)
+}
+
+// remember
+//
+// English:
+//
+//  Records the ID of the event and returns false if it was already recorded
+//
+// Português:
+//
+//  Registra o ID do evento e retorna false se ele já estava registrado
+func (e *userEvents) remember(event UserEvent) (recorded bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, found := e.seen[event.ID]; found == true {
+		return
+	}
+
+	e.seen[event.ID] = event.LTime
+	recorded = true
+	return
+}
+
+// coalesce
+//
+// English:
+//
+//  Holds the event for the window and delivers only the most recent event with the same name
+//  received during the window
+//
+// Português:
+//
+//  Segura o evento pela janela e entrega apenas o evento mais recente com o mesmo nome recebido
+//  durante a janela
+func (e *userEvents) coalesce(event UserEvent, window time.Duration, deliver func(event UserEvent)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if pending := e.pending[event.Name]; pending != nil {
+		if event.LTime > pending.LTime {
+			event.Coalesced = pending.Coalesced + 1
+			*pending = event
+		} else {
+			pending.Coalesced += 1
+		}
+		return
+	}
+
+	e.pending[event.Name] = &event
+	time.AfterFunc(window, func() {
+		e.mutex.Lock()
+		var pending = e.pending[event.Name]
+		delete(e.pending, event.Name)
+		e.mutex.Unlock()
+
+		deliver(*pending)
+	})
+}
+
+// prune
+//
+// English:
+//
+//  Forgets the IDs older than kUserEventBuffer
+//
+// Português:
+//
+//  Esquece os IDs mais antigos que kUserEventBuffer
+func (e *userEvents) prune() {
+	var now = e.clock.time()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for id, lTime := range e.seen {
+		if lTime+kUserEventBuffer < now {
+			delete(e.seen, id)
+		}
+	}
+}